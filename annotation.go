@@ -0,0 +1,129 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// annotationEnabled reports whether failures are written as GitHub Actions
+// annotations. It is initialized by the environment variable
+// XYCOND_ANNOTATIONS, or enabled when running in GitHub Actions.
+var annotationEnabled atomic.Bool
+
+// assertAnnotationEnabled reports whether panics of Assert and contracts are
+// written as annotations. It is initialized by the environment variable
+// XYCOND_ASSERT_ANNOTATIONS.
+var assertAnnotationEnabled atomic.Bool
+
+func init() {
+	annotationEnabled.Store(envAnnotations())
+	var v, _ = strconv.ParseBool(os.Getenv("XYCOND_ASSERT_ANNOTATIONS"))
+	assertAnnotationEnabled.Store(v)
+}
+
+func envAnnotations() bool {
+	var v, err = strconv.ParseBool(os.Getenv("XYCOND_ANNOTATIONS"))
+	if err == nil {
+		return v
+	}
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// SetAnnotations enables or disables writing GitHub Actions error annotations
+// when Test fails. It is enabled by default when running in GitHub Actions.
+func SetAnnotations(enabled bool) {
+	annotationEnabled.Store(enabled)
+}
+
+// SetAssertAnnotations enables or disables writing GitHub Actions error
+// annotations when Assert or a contract panics. It is disabled by default
+// because the panics may be recovered on purpose, e.g. by ExpectPanic.
+func SetAssertAnnotations(enabled bool) {
+	assertAnnotationEnabled.Store(enabled)
+}
+
+// annotate writes the failure of Test as an annotation if annotations are
+// enabled.
+func annotate(e FailureEvent) {
+	if annotationEnabled.Load() {
+		writeAnnotation(e)
+	}
+}
+
+// annotatePanic writes the failure of Assert or a contract as an annotation
+// if annotations of panics are enabled.
+func annotatePanic(e FailureEvent) {
+	if assertAnnotationEnabled.Load() {
+		writeAnnotation(e)
+	}
+}
+
+// writeAnnotation writes the failure as an error annotation to the stdout,
+// which is scanned by GitHub Actions for workflow commands.
+func writeAnnotation(e FailureEvent) {
+	var props []string
+	if e.File != "" {
+		props = append(props,
+			"file="+escapeProperty(workspacePath(e.File)),
+			"line="+strconv.Itoa(e.Line))
+	}
+	var title = "xycond: " + e.Op
+	if e.Test != "" {
+		title = e.Test + ": " + e.Op
+	}
+	props = append(props, "title="+escapeProperty(title))
+
+	fmt.Fprintf(os.Stdout, "::error %s::%s\n",
+		strings.Join(props, ","), escapeData(e.Message))
+}
+
+// workspacePath returns the path relative to the GitHub workspace, which is
+// required to show annotations on the diff.
+func workspacePath(path string) string {
+	var workspace = os.Getenv("GITHUB_WORKSPACE")
+	if workspace == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(workspace, path); err == nil &&
+		!strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+var dataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+
+var propertyEscaper = strings.NewReplacer(
+	"%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+func escapeData(s string) string {
+	return dataEscaper.Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return propertyEscaper.Replace(s)
+}
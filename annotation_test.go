@@ -0,0 +1,99 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+	"github.com/xybor-x/xyerror"
+)
+
+// captureStdout returns what is written to the stdout while calling f.
+func captureStdout(t *testing.T, f func()) string {
+	var r, w, err = os.Pipe()
+	xycond.ExpectNil(err).Test(t)
+
+	var stdout = os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	f()
+	w.Close()
+
+	var data, _ = io.ReadAll(r)
+	return string(data)
+}
+
+// failedtest is a mocktest which is marked as failed.
+type failedtest struct {
+	mocktest
+}
+
+func (failedtest) Failed() bool { return true }
+
+func TestAnnotations(t *testing.T) {
+	var dir, _ = os.Getwd()
+	t.Setenv("GITHUB_WORKSPACE", filepath.Dir(dir))
+
+	xycond.SetAnnotations(true)
+	defer xycond.SetAnnotations(false)
+
+	var out = captureStdout(t, func() {
+		xycond.ExpectEqual("a,b", "c\nd").Test(failedtest{mocktest{"TestFoo"}})
+	})
+	xycond.ExpectIn("::error file="+filepath.Base(dir)+"/annotation_test.go,"+
+		"line=", out).Test(t)
+	xycond.ExpectIn(`,title=TestFoo%3A equal::"a,b" != "c\nd"`, out).Test(t)
+
+	// Panics may be recovered on purpose and failures of mocks are
+	// intentional, so they aren't annotated.
+	out = captureStdout(t, func() {
+		xycond.ExpectPanic(xyerror.AssertionError, func() {
+			xycond.ExpectTrue(false).Assert("foo\nbar")
+		}).Test(t)
+		xycond.ExpectTrue(false).Test(mocktest{})
+	})
+	xycond.ExpectNotIn("::error", out).Test(t)
+
+	// Panics are annotated if it is enabled explicitly.
+	xycond.SetAssertAnnotations(true)
+	defer xycond.SetAssertAnnotations(false)
+	out = captureStdout(t, func() {
+		xycond.ExpectPanic(xyerror.AssertionError, func() {
+			xycond.ExpectTrue(false).Assert("foo\nbar")
+		}).Test(t)
+		xycond.ExpectPanic(xycond.PreconditionError, func() {
+			xycond.Require(xycond.ExpectTrue(false), "param x")
+		}).Test(t)
+	})
+	xycond.ExpectIn(",title=xycond%3A true::foo%0Abar\n", out).Test(t)
+	xycond.ExpectIn("::param x: expect false to be true", out).Test(t)
+
+	xycond.SetAnnotations(false)
+	out = captureStdout(t, func() {
+		xycond.ExpectTrue(false).Test(failedtest{})
+	})
+	xycond.ExpectNotIn("::error", out).Test(t)
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xybor-x/xyerror"
+)
+
+// Argument guards an argument of a function, e.g.
+//
+//	xycond.Arg("timeout", timeout).Positive()
+//	xycond.Arg("name", name).NotEmpty().MaxLen(64)
+//
+// A failed guard raises or returns a xyerror.ValueError, e.g.
+//
+//	ValueError: invalid argument "timeout": expect positive, got -1s
+//
+// Guards after the first failed one are skipped, so they don't panic on an
+// argument of a wrong type.
+type Argument struct {
+	name   string
+	value  any
+	panics bool
+	err    error
+}
+
+// Arg returns an Argument which panics if a guard fails. Guards panic
+// regardless of the Policy because an invalid argument is a bug of the
+// caller.
+func Arg(name string, v any) *Argument {
+	return &Argument{name: name, value: v, panics: true}
+}
+
+// CheckArg returns an Argument which keeps the error of the first failed
+// guard, it is used by functions which can't panic.
+//
+//	if err := xycond.CheckArg("port", port).Positive().Err(); err != nil {
+//		return err
+//	}
+func CheckArg(name string, v any) *Argument {
+	return &Argument{name: name, value: v}
+}
+
+// Err returns the error of the first failed guard, or nil.
+func (a *Argument) Err() error {
+	return a.err
+}
+
+// That guards the argument by a Condition.
+func (a *Argument) That(c Condition) *Argument {
+	if a.err != nil || c.result {
+		return a
+	}
+	a.err = xyerror.ValueError.Newf("invalid argument %q: %s",
+		a.name, c.generateMessage())
+	if a.panics {
+		panic(a.err)
+	}
+	return a
+}
+
+// Positive guards that the number is greater than zero.
+func (a *Argument) Positive() *Argument {
+	if a.err != nil {
+		return a
+	}
+	return a.expect(a.sign() > 0, "positive")
+}
+
+// Negative guards that the number is less than zero.
+func (a *Argument) Negative() *Argument {
+	if a.err != nil {
+		return a
+	}
+	return a.expect(a.sign() < 0, "negative")
+}
+
+// NotNegative guards that the number is not less than zero.
+func (a *Argument) NotNegative() *Argument {
+	if a.err != nil {
+		return a
+	}
+	return a.expect(a.sign() >= 0, "not negative")
+}
+
+// NotZero guards that the argument is not the zero value of its type.
+func (a *Argument) NotZero() *Argument {
+	var v = reflect.ValueOf(a.value)
+	return a.expect(v.IsValid() && !v.IsZero(), "not zero")
+}
+
+// NotNil guards that the argument is not nil.
+func (a *Argument) NotNil() *Argument {
+	return a.expect(!isNil(a.value), "not nil")
+}
+
+// NotEmpty guards that the string, slice, map, array, or channel is not
+// empty.
+func (a *Argument) NotEmpty() *Argument {
+	if a.err != nil {
+		return a
+	}
+	return a.expect(a.length() > 0, "not empty")
+}
+
+// MinLen guards that the length of the string, slice, map, array, or channel
+// is at least n. The length of a string is its number of runes.
+func (a *Argument) MinLen(n int) *Argument {
+	if a.err != nil {
+		return a
+	}
+	var length = a.length()
+	return a.That(NewCondition(length >= n, func() string {
+		return fmt.Sprintf("expect length at least %d, got %d", n, length)
+	}))
+}
+
+// MaxLen guards that the length of the string, slice, map, array, or channel
+// is at most n. The length of a string is its number of runes.
+func (a *Argument) MaxLen(n int) *Argument {
+	if a.err != nil {
+		return a
+	}
+	var length = a.length()
+	return a.That(NewCondition(length <= n, func() string {
+		return fmt.Sprintf("expect length at most %d, got %d", n, length)
+	}))
+}
+
+// OneOf guards that the argument deeply equals one of the options, the options
+// must have the type of the argument.
+func (a *Argument) OneOf(options ...any) *Argument {
+	var ok = false
+	for i := range options {
+		if reflect.DeepEqual(options[i], a.value) {
+			ok = true
+			break
+		}
+	}
+	return a.That(NewCondition(ok, func() string {
+		var values = make([]string, len(options))
+		for i := range options {
+			values[i] = Sprint(options[i])
+		}
+		return fmt.Sprintf("expect one of %s, got %s",
+			strings.Join(values, ", "), Sprint(a.value))
+	}))
+}
+
+// expect guards the argument by the result, the message is "expect <want>,
+// got <argument>".
+func (a *Argument) expect(ok bool, want string) *Argument {
+	return a.That(NewCondition(ok, func() string {
+		return fmt.Sprintf("expect %s, got %s", want, Sprint(a.value))
+	}))
+}
+
+// sign returns -1, 0, or 1 by the sign of the number. It panics if the
+// argument is not a number.
+func (a *Argument) sign() int {
+	var v = reflect.ValueOf(a.value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return compareZero(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return compareZero(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareZero(v.Float())
+	}
+	Panicf("argument %q of %T is not a number", a.name, a.value)
+	return 0
+}
+
+func compareZero[t number](n t) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
+
+// length returns the length of the argument. It panics if the argument
+// doesn't have a length.
+func (a *Argument) length() int {
+	var v = reflect.ValueOf(a.value)
+	switch v.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(v.String())
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+		return v.Len()
+	}
+	Panicf("argument %q of %T doesn't have a length", a.name, a.value)
+	return 0
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xybor-x/xycond"
+	"github.com/xybor-x/xyerror"
+)
+
+func expectArgPanic(t *testing.T, msg string, f func()) {
+	defer func() {
+		var err, _ = recover().(error)
+		xycond.ExpectError(err, xyerror.ValueError).Test(t)
+		xycond.ExpectFalse(errors.Is(err, xyerror.AssertionError)).Test(t)
+		xycond.ExpectEqual(err.Error(), "ValueError: "+msg).Test(t)
+	}()
+	f()
+}
+
+func TestArg(t *testing.T) {
+	xycond.Arg("timeout", time.Second).Positive().NotZero()
+	xycond.Arg("n", -1.5).Negative()
+	xycond.Arg("n", uint(0)).NotNegative()
+	xycond.Arg("name", "xycond").NotEmpty().MinLen(6).MaxLen(6)
+	xycond.Arg("ptr", new(int)).NotNil()
+	xycond.Arg("mode", "r").OneOf("r", "w")
+	xycond.Arg("list", []int{1}).OneOf([]int{0}, []int{1})
+	xycond.Arg("list", []int{1}).That(xycond.ExpectIn(1, []int{1}))
+
+	expectArgPanic(t, `invalid argument "timeout": expect positive, got -1s`,
+		func() { xycond.Arg("timeout", -time.Second).Positive() })
+	expectArgPanic(t, `invalid argument "n": expect negative, got 0`,
+		func() { xycond.Arg("n", 0).Negative() })
+	expectArgPanic(t, `invalid argument "n": expect not negative, got -1`,
+		func() { xycond.Arg("n", -1).NotNegative() })
+	expectArgPanic(t, `invalid argument "n": expect not zero, got 0`,
+		func() { xycond.Arg("n", 0).NotZero() })
+	expectArgPanic(t, `invalid argument "ptr": expect not nil, got (*int)(nil)`,
+		func() { xycond.Arg("ptr", (*int)(nil)).NotNil() })
+	expectArgPanic(t, `invalid argument "name": expect not empty, got ""`,
+		func() { xycond.Arg("name", "").NotEmpty().MaxLen(64) })
+	expectArgPanic(t, `invalid argument "name": expect length at most 2, got 3`,
+		func() { xycond.Arg("name", "abc").NotEmpty().MaxLen(2) })
+	expectArgPanic(t, `invalid argument "s": expect length at least 2, got 1`,
+		func() { xycond.Arg("s", []int{1}).MinLen(2) })
+	expectArgPanic(t,
+		`invalid argument "mode": expect one of "r", "w", got "x"`,
+		func() { xycond.Arg("mode", "x").OneOf("r", "w") })
+	expectArgPanic(t,
+		`invalid argument "list": expect one of []int{0}, got []int{1}`,
+		func() { xycond.Arg("list", []int{1}).OneOf([]int{0}) })
+	expectArgPanic(t, `invalid argument "n": 1 is not less than 0`,
+		func() { xycond.Arg("n", 1).That(xycond.ExpectLessThan(1, 0)) })
+}
+
+func TestArgInvalidUse(t *testing.T) {
+	xycond.ExpectPanic(xyerror.AssertionError, func() {
+		xycond.Arg("s", "a").Positive()
+	}).Test(t)
+	xycond.ExpectPanic(xyerror.AssertionError, func() {
+		xycond.Arg("n", 1).MaxLen(1)
+	}).Test(t)
+}
+
+func TestCheckArg(t *testing.T) {
+	xycond.ExpectNil(xycond.CheckArg("port", 80).Positive().Err()).Test(t)
+
+	var err = xycond.CheckArg("name", "").NotEmpty().MaxLen(-1).Err()
+	xycond.ExpectError(err, xyerror.ValueError).Test(t)
+	xycond.ExpectEqual(err.Error(),
+		`ValueError: invalid argument "name": expect not empty, got ""`).
+		Test(t)
+
+	// Guards after the first failed one don't check the type of the
+	// argument.
+	err = xycond.CheckArg("p", (*string)(nil)).NotNil().NotEmpty().MinLen(1).
+		MaxLen(1).Positive().Negative().NotNegative().Err()
+	xycond.ExpectEqual(err.Error(),
+		`ValueError: invalid argument "p": expect not nil, got (*string)(nil)`).
+		Test(t)
+}
@@ -32,6 +32,24 @@ func AssertNotEqual(a, b any) {
 	ExpectNotEqual(a, b).Assert("")
 }
 
+// AssertEq is the same as AssertEqual, but the parameters have the same type
+// and they are only converted to interfaces if they are different, so it
+// doesn't allocate if it passes.
+func AssertEq[T comparable](a, b T) {
+	if a != b {
+		ExpectEqual(a, b).Assert("")
+	}
+}
+
+// AssertNotEq is the same as AssertNotEqual, but the parameters have the same
+// type and they are only converted to interfaces if they are equal, so it
+// doesn't allocate if it passes.
+func AssertNotEq[T comparable](a, b T) {
+	if a == b {
+		ExpectNotEqual(a, b).Assert("")
+	}
+}
+
 // AssertLessThan panics if a is not less than b.
 func AssertLessThan[t number](a, b t) {
 	ExpectLessThan(a, b).Assert("")
@@ -137,13 +155,13 @@ func AssertErrorNot(err error, targets ...error) {
 	ExpectErrorNot(err, targets...).Assert("")
 }
 
-// AssertIn panics if the element is in the object. The object must be an array,
-// slice, string, or map.
+// AssertIn panics if the element is not in the object. The object must be an
+// array, slice, string, or map.
 func AssertIn(element any, object any) {
 	ExpectIn(element, object).Assert("")
 }
 
-// AssertNotIn panics if the element is not in the object. The object must be an
+// AssertNotIn panics if the element is in the object. The object must be an
 // array, slice, string, or map.
 func AssertNotIn(element any, object any) {
 	ExpectNotIn(element, object).Assert("")
@@ -151,10 +169,20 @@ func AssertNotIn(element any, object any) {
 
 // AssertTrue panics if the condition is false.
 func AssertTrue(b bool) {
-	ExpectTrue(b).Assert("")
+	expectBool(b, opTrue, "AssertTrue").Assert("")
 }
 
 // AssertFalse panics if the condition is true.
 func AssertFalse(b bool) {
-	ExpectFalse(b).Assert("")
+	expectBool(!b, opFalse, "AssertFalse").Assert("")
+}
+
+// Assert panics if the actual value doesn't match the Matcher.
+func Assert(actual any, m Matcher) {
+	Expect(actual, m).Assert("")
+}
+
+// AssertNot panics if the actual value matches the Matcher.
+func AssertNot(actual any, m Matcher) {
+	ExpectNot(actual, m).Assert("")
 }
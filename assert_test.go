@@ -21,7 +21,10 @@
 package xycond_test
 
 import (
+	"errors"
+	"io"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/xybor-x/xycond"
@@ -42,6 +45,22 @@ func TestAssert(t *testing.T) {
 func TestAssertEqual(t *testing.T) {
 	xycond.AssertEqual(1, 1)
 	xycond.AssertNotEqual(1, 2)
+	xycond.AssertNotEqual(errors.New("foo"), nil)
+	xycond.AssertEq("foo", "foo")
+	xycond.AssertNotEq(1, 2)
+
+	// The signatures of interface assertions are kept.
+	var _ func(a, b any) = xycond.AssertEqual
+	var _ func(a, b any) = xycond.AssertNotEqual
+	var _ func(a any) = xycond.AssertEmpty
+	var _ func(element, object any) = xycond.AssertIn
+
+	expectPanicMessage(t, "1000 != 1001", func() {
+		xycond.AssertEq(1000, 1001)
+	})
+	expectPanicMessage(t, `got the same value ("foo")`, func() {
+		xycond.AssertNotEq("foo", "foo")
+	})
 }
 
 func TestAssertLessThan(t *testing.T) {
@@ -155,3 +174,87 @@ func TestAssertTrue(t *testing.T) {
 	xycond.AssertTrue(true)
 	xycond.AssertFalse(false)
 }
+
+func TestAssertMatcher(t *testing.T) {
+	var even = xycond.NewMatcher("an even number", func(a any) bool {
+		return a.(int)%2 == 0
+	})
+	xycond.Assert(2, even)
+	xycond.AssertNot(3, even)
+}
+
+func TestAssertNoAllocs(t *testing.T) {
+	// Values which aren't known at compile time are boxed on the heap if they
+	// are converted to interfaces, so pointers, maps, and channels are passed
+	// to assertions taking interfaces.
+	var n, _ = strconv.Atoi("1000")
+	var str = strconv.Itoa(n)
+	var a = [2]string{str, str + str}
+	var p, q = &n, new(int)
+	var m = map[*int]struct{}{p: {}}
+	var empty = map[*int]struct{}{}
+	var ch = make(chan int)
+	var err = errors.New("foo")
+	var even = xycond.NewMatcher("an even number", func(a any) bool {
+		return *a.(*int)%2 == 0
+	})
+	var odd = xycond.Not(even)
+
+	xycond.ExpectNoAllocs(func() {
+		xycond.AssertEqual(p, p)
+		xycond.AssertNotEqual(p, q)
+		xycond.AssertEq(n, n)
+		xycond.AssertEq(str, str)
+		xycond.AssertEq(a, a)
+		xycond.AssertNotEq(n, n+1)
+		xycond.AssertNotEq(p, nil)
+		xycond.AssertLessThan(n, n+1)
+		xycond.AssertNotLessThan(n, n)
+		xycond.AssertGreaterThan(n+1, n)
+		xycond.AssertNotGreaterThan(n, n)
+		xycond.AssertZero(n - n)
+		xycond.AssertNotZero(n)
+		xycond.AssertNil(nil)
+		xycond.AssertNotNil(p)
+		xycond.AssertEmpty(empty)
+		xycond.AssertNotEmpty(m)
+		xycond.AssertIs(m, reflect.Map)
+		xycond.AssertIsNot(p, reflect.Slice, reflect.Map)
+		xycond.AssertSame(p, q)
+		xycond.AssertNotSame(p, ch)
+		xycond.AssertWritable(ch)
+		xycond.AssertNotWritable((<-chan int)(ch))
+		xycond.AssertReadable(ch)
+		xycond.AssertNotReadable((chan<- int)(ch))
+		xycond.AssertError(err, err)
+		xycond.AssertErrorNot(err, io.EOF)
+		xycond.AssertIn(p, m)
+		xycond.AssertNotIn(q, m)
+		xycond.AssertTrue(n > 0)
+		xycond.AssertFalse(n < 0)
+		xycond.Assert(p, even)
+		xycond.AssertNot(p, odd)
+	}).Test(t)
+}
+
+func TestAssertAllocs(t *testing.T) {
+	var n, _ = strconv.Atoi("1000")
+	var s, none = []int{n}, []int(nil)
+	var key, missing = strconv.Itoa(n), strconv.Itoa(n + 1)
+	var m = map[string]int{key: n}
+
+	// Values are boxed by callers if they are passed as interfaces.
+	xycond.ExpectAllocsAtMost(2, func() { xycond.AssertEqual(n, n) }).Test(t)
+	xycond.ExpectAllocsAtMost(2, func() { xycond.AssertNotEqual(key, n) }).
+		Test(t)
+	xycond.ExpectAllocsAtMost(1, func() { xycond.AssertEmpty(none) }).Test(t)
+	xycond.ExpectAllocsAtMost(1, func() { xycond.AssertNotEmpty(s) }).Test(t)
+	xycond.ExpectAllocsAtMost(2, func() { xycond.AssertIn(n, s) }).Test(t)
+	xycond.ExpectAllocsAtMost(1, func() { xycond.AssertNotNil(s) }).Test(t)
+	xycond.ExpectAllocsAtMost(1, func() { xycond.AssertNil(none) }).Test(t)
+
+	// The key and the found value are copied to look a key up in a map.
+	xycond.ExpectAllocsAtMost(2, func() { xycond.AssertIn(key, m) }).Test(t)
+	xycond.ExpectAllocsAtMost(1, func() { xycond.AssertNotIn(missing, m) }).
+		Test(t)
+}
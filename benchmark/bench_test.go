@@ -21,12 +21,22 @@
 package benchmark
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/xybor-x/xycond"
 )
 
+var errBench = errors.New("bench")
+
+type benchMatcher struct{}
+
+func (benchMatcher) Match(actual any) bool            { return actual != nil }
+func (benchMatcher) FailureMessage(any) string        { return "" }
+func (benchMatcher) NegatedFailureMessage(any) string { return "" }
+
 var largeKeys []string
 var largeString string
 var largeMap map[string]any
@@ -94,3 +104,68 @@ func BenchmarkExpectIn(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkAssert(b *testing.B) {
+	var s = []int{1, 2, 3}
+	var m = map[string]int{"a": 1}
+	var ch = make(chan int)
+	var p = &struct{}{}
+	var err = errBench
+
+	var benchmarks = []struct {
+		name string
+		f    func(i int)
+	}{
+		{"Equal", func(i int) { xycond.AssertEqual(i, i) }},
+		{"NotEqual", func(i int) { xycond.AssertNotEqual(i, i+1) }},
+		{"Eq", func(i int) { xycond.AssertEq(i, i) }},
+		{"NotEq", func(i int) { xycond.AssertNotEq(i, i+1) }},
+		{"LessThan", func(i int) { xycond.AssertLessThan(i, i+1) }},
+		{"NotLessThan", func(i int) { xycond.AssertNotLessThan(i, i) }},
+		{"GreaterThan", func(i int) { xycond.AssertGreaterThan(i+1, i) }},
+		{"NotGreaterThan", func(i int) {
+			xycond.AssertNotGreaterThan(i, i)
+		}},
+		{"Panic", func(i int) {
+			xycond.AssertPanic(errBench, func() { panic(errBench) })
+		}},
+		{"Zero", func(i int) { xycond.AssertZero(i - i) }},
+		{"NotZero", func(i int) { xycond.AssertNotZero(i + 1) }},
+		{"Nil", func(i int) { xycond.AssertNil(nil) }},
+		{"NotNil", func(i int) { xycond.AssertNotNil(p) }},
+		{"Empty", func(i int) { xycond.AssertEmpty(s[:0]) }},
+		{"NotEmpty", func(i int) { xycond.AssertNotEmpty(s) }},
+		{"Is", func(i int) { xycond.AssertIs(s, reflect.Slice) }},
+		{"IsNot", func(i int) { xycond.AssertIsNot(s, reflect.Map) }},
+		{"Same", func(i int) { xycond.AssertSame(i, i+1) }},
+		{"NotSame", func(i int) { xycond.AssertNotSame(i, s) }},
+		{"Writable", func(i int) { xycond.AssertWritable(ch) }},
+		{"NotWritable", func(i int) {
+			xycond.AssertNotWritable((<-chan int)(ch))
+		}},
+		{"Readable", func(i int) { xycond.AssertReadable(ch) }},
+		{"NotReadable", func(i int) {
+			xycond.AssertNotReadable((chan<- int)(ch))
+		}},
+		{"Error", func(i int) { xycond.AssertError(err, errBench) }},
+		{"ErrorNot", func(i int) {
+			xycond.AssertErrorNot(err, errors.ErrUnsupported)
+		}},
+		{"In", func(i int) { xycond.AssertIn(i%3+1, s) }},
+		{"NotIn", func(i int) { xycond.AssertNotIn("b", m) }},
+		{"True", func(i int) { xycond.AssertTrue(i >= 0) }},
+		{"False", func(i int) { xycond.AssertFalse(i < 0) }},
+		{"Matcher", func(i int) { xycond.Assert(p, benchMatcher{}) }},
+		{"NotMatcher", func(i int) { xycond.AssertNot(nil, benchMatcher{}) }},
+	}
+
+	for _, bm := range benchmarks {
+		var f = bm.f
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				f(i)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// Case is a case of a table-driven test.
+type Case[In, Out any] struct {
+	// Name is the name of the subtest, it is "case#<index>" if empty.
+	Name string
+
+	// In is passed to the tested function.
+	In In
+
+	// Want is the expected output of the tested function.
+	Want Out
+
+	// Only skips cases which are not Only if there is at least one Only case.
+	Only bool
+
+	// Skip skips the case.
+	Skip bool
+
+	// Parallel runs the case in parallel with other Parallel cases.
+	Parallel bool
+}
+
+// runner instances may be *testing.T, whose subtests are of the same type.
+type runner[T any] interface {
+	failer
+	Helper()
+	Cleanup(f func())
+	Log(args ...any)
+	Skip(args ...any)
+	Parallel()
+	Run(name string, f func(t T)) bool
+}
+
+// caseFailure is a failed case in the summary of Cases.
+type caseFailure struct {
+	name    string
+	message string
+}
+
+// Cases runs each case as a subtest, which passes its input to fn and tests
+// the Condition returned by check with the output and the wanted output. A
+// summary of failed cases is logged after all cases complete. The test is
+// usually a *testing.T.
+func Cases[In, Out any, T runner[T]](
+	t T,
+	cases []Case[In, Out],
+	fn func(In) Out,
+	check func(got, want Out) Condition,
+) {
+	t.Helper()
+	var _, file, line, _ = runtime.Caller(1)
+
+	var only = false
+	for i := range cases {
+		only = only || cases[i].Only
+	}
+
+	var lock sync.Mutex
+	var failures []caseFailure
+	t.Cleanup(func() {
+		if len(failures) > 0 {
+			t.Log(summarizeCases(len(cases), failures))
+		}
+	})
+
+	for i := range cases {
+		var tc = cases[i]
+		var name = tc.Name
+		if name == "" {
+			name = fmt.Sprintf("case#%d", i)
+		}
+
+		t.Run(name, func(t T) {
+			switch {
+			case tc.Skip:
+				t.Skip("skipped by Skip")
+			case only && !tc.Only:
+				t.Skip("skipped by Only")
+			}
+			if tc.Parallel {
+				t.Parallel()
+			}
+
+			var c = check(fn(tc.In), tc.Want)
+			if !c.OK() {
+				lock.Lock()
+				failures = append(failures,
+					caseFailure{name: name, message: c.Message()})
+				lock.Unlock()
+				c.test(t, file, line)
+			}
+		})
+	}
+}
+
+func summarizeCases(total int, failures []caseFailure) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d of %d cases failed:\n", len(failures), total)
+
+	var w = tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CASE\tMESSAGE")
+	for _, f := range failures {
+		var msg = strings.ReplaceAll(f.message, "\n", " ")
+		fmt.Fprintf(w, "%s\t%s\n", f.name, msg)
+	}
+	w.Flush()
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+)
+
+func TestCases(t *testing.T) {
+	var calls atomic.Int32
+	var upper = func(s string) string {
+		calls.Add(1)
+		return strings.ToUpper(s)
+	}
+
+	t.Run("all", func(t *testing.T) {
+		xycond.Cases(t, []xycond.Case[string, string]{
+			{Name: "empty", In: "", Want: ""},
+			{In: "foo", Want: "FOO", Parallel: true},
+			{In: "bar", Want: "BAR", Parallel: true},
+			{Name: "skipped", In: "foo", Want: "foo", Skip: true},
+		}, upper, func(got, want string) xycond.Condition {
+			return xycond.ExpectEqual(got, want)
+		})
+	})
+	xycond.ExpectEqual(calls.Load(), int32(3)).Test(t)
+
+	calls.Store(0)
+	t.Run("only", func(t *testing.T) {
+		xycond.Cases(t, []xycond.Case[string, string]{
+			{In: "foo", Want: "foo"},
+			{In: "bar", Want: "BAR", Only: true},
+		}, upper, func(got, want string) xycond.Condition {
+			return xycond.ExpectEqual(got, want)
+		})
+	})
+	xycond.ExpectEqual(calls.Load(), int32(1)).Test(t)
+}
+
+// fakeRunner runs subtests of Cases in sequence and records their failures
+// and logs.
+type fakeRunner struct {
+	failed   bool
+	logs     []string
+	cleanups []func()
+}
+
+func (r *fakeRunner) Fail()            { r.failed = true }
+func (r *fakeRunner) Helper()          {}
+func (r *fakeRunner) Cleanup(f func()) { r.cleanups = append(r.cleanups, f) }
+func (r *fakeRunner) Skip(args ...any) {}
+func (r *fakeRunner) Parallel()        {}
+
+func (r *fakeRunner) Log(args ...any) {
+	r.logs = append(r.logs, fmt.Sprint(args...))
+}
+
+func (r *fakeRunner) Run(name string, f func(t *fakeRunner)) bool {
+	var sub = &fakeRunner{}
+	f(sub)
+	r.failed = r.failed || sub.failed
+	return !sub.failed
+}
+
+func TestCasesFailure(t *testing.T) {
+	var r = &fakeRunner{}
+	captureStdout(t, func() {
+		xycond.Cases(r, []xycond.Case[string, string]{
+			{Name: "upper", In: "foo", Want: "FOO"},
+			{Name: "lower", In: "bar", Want: "bar"},
+			{In: "baz", Want: "baz"},
+		}, strings.ToUpper, func(got, want string) xycond.Condition {
+			return xycond.ExpectEqual(got, want)
+		})
+	})
+	for _, f := range r.cleanups {
+		f()
+	}
+
+	xycond.ExpectTrue(r.failed).Test(t)
+	xycond.ExpectEqual(len(r.logs), 1).Test(t)
+	xycond.ExpectEqual(r.logs[0], "2 of 3 cases failed:\n"+
+		"CASE    MESSAGE\n"+
+		"lower   \"BAR\" != \"bar\"\n"+
+		"case#2  \"BAZ\" != \"baz\"").Test(t)
+}
@@ -22,10 +22,10 @@
 package xycond
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
-	"runtime"
 	"strconv"
 	"strings"
 
@@ -74,40 +74,121 @@ const (
 	opNotIn
 	opTrue
 	opFalse
+	opSnapshot
+	opMatch
+	opNotMatch
+	opCustom
+	opRoundTrip
+	opNoPanic
+	opAllocsAtMost
+	opNoAllocs
+	opFasterThan
+	opHeapGrowthAtMost
+	opFileExists
+	opDirExists
+	opNoFile
+	opFileContent
+	opFileMode
+	opFileChecksum
+	opTree
+	opDone
+	opNotDone
+	opCanceled
+	opDeadlineWithin
+	opContextValue
+	opCompletesWithin
+	opBlocks
 )
 
+var operatorNames = [...]string{
+	opEqual:            "equal",
+	opNotEqual:         "not_equal",
+	opLessThan:         "less_than",
+	opNotLessThan:      "not_less_than",
+	opGreaterThan:      "greater_than",
+	opNotGreaterThan:   "not_greater_than",
+	opPanic:            "panic",
+	opNil:              "nil",
+	opNotNil:           "not_nil",
+	opEmpty:            "empty",
+	opNotEmpty:         "not_empty",
+	opIs:               "is",
+	opIsNot:            "is_not",
+	opSame:             "same",
+	opNotSame:          "not_same",
+	opWritable:         "writable",
+	opNotWritable:      "not_writable",
+	opReadable:         "readable",
+	opNotReadable:      "not_readable",
+	opError:            "error",
+	opErrorNot:         "error_not",
+	opIn:               "in",
+	opNotIn:            "not_in",
+	opTrue:             "true",
+	opFalse:            "false",
+	opSnapshot:         "snapshot",
+	opMatch:            "match",
+	opNotMatch:         "not_match",
+	opCustom:           "custom",
+	opRoundTrip:        "round_trip",
+	opNoPanic:          "no_panic",
+	opAllocsAtMost:     "allocs_at_most",
+	opNoAllocs:         "no_allocs",
+	opFasterThan:       "faster_than",
+	opHeapGrowthAtMost: "heap_growth_at_most",
+	opFileExists:       "file_exists",
+	opDirExists:        "dir_exists",
+	opNoFile:           "no_file",
+	opFileContent:      "file_content",
+	opFileMode:         "file_mode",
+	opFileChecksum:     "file_checksum",
+	opTree:             "tree",
+	opDone:             "done",
+	opNotDone:          "not_done",
+	opCanceled:         "canceled",
+	opDeadlineWithin:   "deadline_within",
+	opContextValue:     "context_value",
+	opCompletesWithin:  "completes_within",
+	opBlocks:           "blocks",
+}
+
+// String returns the stable name of the operator.
+func (op operator) String() string {
+	return operatorNames[op]
+}
+
 // ExpectEqual returns a true Condition if the two values are equal.
 func ExpectEqual(a, b any) Condition {
-	return Condition{result: a == b, op: opEqual, params: []any{a, b}}
+	return newCondition(a == b, opEqual, a, b)
 }
 
 // ExpectNotEqual returns a true Condition if the two values are not equal.
 func ExpectNotEqual(a, b any) Condition {
-	return ExpectEqual(a, b).revert(opNotEqual)
+	return newCondition(a != b, opNotEqual, a, b)
 }
 
 // ExpectLessThan returns a true Condition if the first parameter is less than
 // the second.
 func ExpectLessThan[t number](a, b t) Condition {
-	return Condition{result: a < b, op: opLessThan, params: []any{a, b}}
+	return compare(a < b, opLessThan, a, b)
 }
 
 // ExpectNotLessThan returns a true Condition if the first parameter is not less
 // than the second.
 func ExpectNotLessThan[t number](a, b t) Condition {
-	return ExpectLessThan(a, b).revert(opNotLessThan)
+	return compare(!(a < b), opNotLessThan, a, b)
 }
 
 // ExpectGreaterThan returns a true Condition if the first parameter is greater
 // than the second.
 func ExpectGreaterThan[t number](a, b t) Condition {
-	return Condition{result: a > b, op: opGreaterThan, params: []any{a, b}}
+	return compare(a > b, opGreaterThan, a, b)
 }
 
 // ExpectNotGreaterThan returns a true Condition if the first parameter is not
 // greater than the second.
 func ExpectNotGreaterThan[t number](a, b t) Condition {
-	return ExpectGreaterThan(a, b).revert(opNotGreaterThan)
+	return compare(!(a > b), opNotGreaterThan, a, b)
 }
 
 // ExpectPanic returns a true Condition if it found a panic with a correct data
@@ -124,8 +205,7 @@ func ExpectPanic(r any, f func()) (c Condition) {
 		} else {
 			c.result = data == r
 		}
-		c.op = opPanic
-		c.params = []any{r, data}
+		c = newCondition(c.result, opPanic, r, data)
 	}()
 
 	f()
@@ -135,200 +215,344 @@ func ExpectPanic(r any, f func()) (c Condition) {
 // ExpectZero returns a true Condition if the parameter is zero.
 func ExpectZero[T number](a T) Condition {
 	var zero T
-	return ExpectEqual(a, zero)
+	return compare(a == zero, opEqual, a, zero)
 }
 
 // ExpectNotZero returns a true Condition if the parameter is not zero.
 func ExpectNotZero[T number](a T) Condition {
-	var zero T = 0
-	return ExpectNotEqual(a, zero)
+	var zero T
+	return compare(a != zero, opNotEqual, a, zero)
 }
 
 // ExpectNil returns a true Condition if the parameter is nil.
 func ExpectNil(a any) Condition {
-	var cond = Condition{result: false, op: opNil, params: []any{a}}
-
-	if a == nil {
-		cond.result = true
-	} else {
-		var va = reflect.ValueOf(a)
-		var expect = ExpectIs(a, reflect.Chan, reflect.Func, reflect.Interface,
-			reflect.Map, reflect.Pointer, reflect.Slice)
-		if expect.result && va.IsNil() {
-			cond.result = true
-		}
-	}
-
-	return cond
+	return newCondition(isNil(a), opNil, a)
 }
 
 // ExpectNotNil returns a true Condition if the parameter is not nil.
 func ExpectNotNil(a any) Condition {
-	return ExpectNil(a).revert(opNotNil)
+	return newCondition(!isNil(a), opNotNil, a)
+}
+
+// isNil returns true if the parameter is nil or a nil channel, function,
+// interface, map, pointer, or slice.
+func isNil(a any) bool {
+	if a == nil {
+		return true
+	}
+	var va = reflect.ValueOf(a)
+	switch va.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map,
+		reflect.Pointer, reflect.Slice:
+		return va.IsNil()
+	}
+	return false
 }
 
 // ExpectEmpty returns a true Condition if the parameter is an empty string,
 // slice, array, or channel.
 func ExpectEmpty(a any) Condition {
-	var va = reflect.ValueOf(a)
-	return Condition{
-		result: va.Len() == 0,
-		op:     opEmpty, params: []any{a, va.Kind()},
-	}
+	return expectEmpty(a, true, opEmpty)
 }
 
 // ExpectNotEmpty returns a true Condition if the parameter is not an empty
 // string, slice, array, or channel.
 func ExpectNotEmpty(a any) Condition {
-	return ExpectEmpty(a).revert(opNotEmpty)
+	return expectEmpty(a, false, opNotEmpty)
+}
+
+func expectEmpty(a any, empty bool, op operator) Condition {
+	return newCondition(isEmpty(a) == empty, op, a, reflect.ValueOf(a).Kind())
+}
+
+// isEmpty returns true if the length of the parameter is zero.
+func isEmpty(a any) bool {
+	return reflect.ValueOf(a).Len() == 0
 }
 
 // ExpectIs returns a true Condition if value belongs to one of passed kinds.
 func ExpectIs(v any, kinds ...reflect.Kind) Condition {
+	return expectIs(v, kinds, true, opIs)
+}
+
+// ExpectIsNot returns a true Condition if value doesn't belong to any passed
+// kinds.
+func ExpectIsNot(v any, kinds ...reflect.Kind) Condition {
+	return expectIs(v, kinds, false, opIsNot)
+}
+
+func expectIs(v any, kinds []reflect.Kind, is bool, op operator) Condition {
 	var kindV = reflect.TypeOf(v).Kind()
-	var cond = Condition{result: false, op: opIs}
+	var found = false
 	for i := range kinds {
 		if kindV == kinds[i] {
-			cond.result = true
+			found = true
 		}
 	}
-	cond.params = []any{kindV, kinds}
-	return cond
-}
 
-// ExpectIsNot returns a true Condition if value doesn't belong to any passed
-// kinds.
-func ExpectIsNot(v any, kinds ...reflect.Kind) Condition {
-	return ExpectIs(v, kinds...).revert(opIsNot)
+	// The kinds are copied by a false Condition only, so the variadic slice
+	// doesn't escape.
+	if found == is {
+		return newCondition(true, op)
+	}
+	return newCondition(false, op, kindV,
+		append([]reflect.Kind(nil), kinds...))
 }
 
 // ExpectSame returns a true Condition if parameters are the same type.
 func ExpectSame(v ...any) Condition {
-	var t0 = reflect.TypeOf(v[0])
-	var cond = Condition{result: true, op: opSame}
-	var types = []string{fmt.Sprint(t0)}
-	for i := 1; i < len(v); i++ {
-		var ti = reflect.TypeOf(v[i])
-		if t0 != ti {
-			cond.result = false
-		}
-		types = append(types, fmt.Sprint(ti))
-	}
-	cond.params = []any{v}
-	return cond
+	return expectSame(v, true, opSame)
 }
 
 // ExpectNotSame returns a true Condition if there is at least one value whose
 // type is different from the rest.
 func ExpectNotSame(v ...any) Condition {
-	return ExpectSame(v...).revert(opNotSame)
+	return expectSame(v, false, opNotSame)
+}
+
+// expectSame keeps the types of the values instead of the values, so they
+// don't escape.
+func expectSame(v []any, same bool, op operator) Condition {
+	var t0 = reflect.TypeOf(v[0])
+	var found = true
+	for i := 1; i < len(v); i++ {
+		if reflect.TypeOf(v[i]) != t0 {
+			found = false
+		}
+	}
+
+	if found == same {
+		return newCondition(true, op)
+	}
+	var types = make([]reflect.Type, len(v))
+	for i := range v {
+		types[i] = reflect.TypeOf(v[i])
+	}
+	return newCondition(false, op, types)
 }
 
 // ExpectWritable returns a true Condition if the channel is writable.
 func ExpectWritable(c any) Condition {
-	AssertIs(c, reflect.Chan)
-	var dir = reflect.TypeOf(c).ChanDir()
-	return Condition{
-		result: dir == reflect.BothDir || dir == reflect.SendDir,
-		op:     opWritable,
-	}
+	return Condition{result: isWritable(c), op: opWritable}
 }
 
 // ExpectNotWritable returns a true Condition if the channel is not writable.
 func ExpectNotWritable(c any) Condition {
-	return ExpectWritable(c).revert(opNotWritable)
+	return Condition{result: !isWritable(c), op: opNotWritable}
+}
+
+func isWritable(c any) bool {
+	ExpectIs(c, reflect.Chan).must()
+	var dir = reflect.TypeOf(c).ChanDir()
+	return dir == reflect.BothDir || dir == reflect.SendDir
 }
 
 // ExpectReadable returns a true Condition if the channel is readable.
 func ExpectReadable(c any) Condition {
-	AssertIs(c, reflect.Chan)
-	var dir = reflect.TypeOf(c).ChanDir()
-	return Condition{
-		result: dir == reflect.BothDir || dir == reflect.RecvDir,
-		op:     opReadable,
-	}
+	return Condition{result: isReadable(c), op: opReadable}
 }
 
 // ExpectNotReadable returns a true Condition if the channel is not readable.
 func ExpectNotReadable(c any) Condition {
-	return ExpectReadable(c).revert(opNotReadable)
+	return Condition{result: !isReadable(c), op: opNotReadable}
+}
+
+func isReadable(c any) bool {
+	ExpectIs(c, reflect.Chan).must()
+	var dir = reflect.TypeOf(c).ChanDir()
+	return dir == reflect.BothDir || dir == reflect.RecvDir
 }
 
 // ExpectError returns a true Condition if err belongs to one of the passed
 // targets.
 func ExpectError(err error, targets ...error) Condition {
-	var cond = Condition{result: false, op: opError}
-	for i := range targets {
-		if errors.Is(err, targets[i]) {
-			cond.result = true
-		}
-	}
-	cond.params = []any{err, targets}
-	return cond
+	return expectError(err, targets, true, opError)
 }
 
 // ExpectErrorNot returns a true Condition if the err doesn't belong to any
 // targets.
 func ExpectErrorNot(err error, targets ...error) Condition {
-	return ExpectError(err, targets...).revert(opErrorNot)
+	return expectError(err, targets, false, opErrorNot)
+}
+
+func expectError(err error, targets []error, is bool, op operator) Condition {
+	var found = false
+	for i := range targets {
+		if errors.Is(err, targets[i]) {
+			found = true
+		}
+	}
+
+	if found == is {
+		return newCondition(true, op)
+	}
+	return newCondition(false, op, err, append([]error(nil), targets...))
 }
 
 // ExpectIn returns a true Condition if the element is in the object. The object
 // must be an array, slice, string, or map.
 func ExpectIn(elem any, obj any) Condition {
-	AssertIs(obj, reflect.Array, reflect.Slice, reflect.String, reflect.Map)
+	if contains(obj, elem) {
+		return newCondition(true, opIn)
+	}
+	return newCondition(false, opIn, elem, obj)
+}
 
-	var objV = reflect.ValueOf(obj)
-	var elemV = reflect.ValueOf(elem)
+// ExpectNotIn returns a true Condition if the element is not in the object. The
+// object must be an array, slice, string, or map.
+func ExpectNotIn(elem any, obj any) Condition {
+	if !contains(obj, elem) {
+		return newCondition(true, opNotIn)
+	}
+	return newCondition(false, opNotIn, elem, obj)
+}
 
-	var cond = Condition{
-		result: false,
-		op:     opIn,
+// contains returns true if the element is in the object.
+func contains(obj any, elem any) bool {
+	ExpectIs(obj, reflect.Array, reflect.Slice, reflect.String, reflect.Map).
+		must()
+
+	if reflect.ValueOf(obj).Kind() == reflect.Map {
+		return containsKey(obj, elem)
 	}
+	return containsElem(obj, elem)
+}
+
+// containsKey returns true if the key is in the map. The key escapes.
+func containsKey(m any, key any) bool {
+	var mV = reflect.ValueOf(m)
+	var keyV = reflect.ValueOf(key)
+	ExpectEqual(mV.Type().Key(), keyV.Type()).must()
+	return mV.MapIndex(keyV).IsValid()
+}
+
+// containsElem returns true if the element is in the array, slice, or string.
+func containsElem(obj any, elem any) bool {
+	var objV = reflect.ValueOf(obj)
+	var elemV = reflect.ValueOf(elem)
 
 	switch objV.Kind() {
-	case reflect.Map:
-		AssertEqual(objV.Type().Key(), elemV.Type())
-		cond.result = objV.MapIndex(elemV) != reflect.Value{}
-		cond.params = []any{elem, "map"}
 	case reflect.Slice, reflect.Array:
-		AssertEqual(objV.Type().Elem(), elemV.Type())
+		ExpectEqual(objV.Type().Elem(), elemV.Type()).must()
 		for i := 0; i < objV.Len(); i++ {
-			if elem == objV.Index(i).Interface() {
-				cond.result = true
-				break
+			if valueEqual(objV.Index(i), elemV) {
+				return true
 			}
 		}
-		cond.params = []any{elem, "array"}
-	case reflect.String:
-		AssertIs(elem, reflect.String, reflect.Int32, reflect.Uint8)
+		return false
+	default:
+		ExpectIs(elem, reflect.String, reflect.Int32, reflect.Uint8).must()
 		switch elemV.Kind() {
 		case reflect.Int32:
-			cond.result = strings.ContainsRune(obj.(string), elem.(rune))
-			cond.params = []any{strconv.QuoteRune(elem.(rune)), obj}
-		case reflect.String:
-			cond.result = strings.Contains(obj.(string), elem.(string))
-			cond.params = []any{strconv.Quote(elem.(string)), obj}
+			return strings.ContainsRune(objV.String(), rune(elemV.Int()))
+		case reflect.Uint8:
+			return strings.IndexByte(objV.String(), byte(elemV.Uint())) >= 0
+		default:
+			return strings.Contains(objV.String(), elemV.String())
 		}
 	}
-
-	return cond
 }
 
-// ExpectNotIn returns a true Condition if the element is not in the object. The
-// object must be an array, slice, string, or map.
-func ExpectNotIn(object any, element any) Condition {
-	return ExpectIn(object, element).revert(opNotIn)
+// valueEqual is the same as reflect.Value.Equal, but it doesn't let the values
+// escape, so callers needn't box them on the heap.
+func valueEqual(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	case reflect.Chan, reflect.Pointer, reflect.UnsafePointer:
+		return a.UnsafePointer() == b.UnsafePointer()
+	case reflect.Array:
+		if !a.Type().Comparable() {
+			break
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !valueEqual(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !valueEqual(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	panic("xycond: values of type " + a.Type().String() +
+		" are not comparable")
 }
 
-// ExpectTrue returns true if the the parameter is true.
+// ExpectTrue returns true if the the parameter is true. The failure message
+// contains the source expression of the parameter if the source file is
+// available.
 func ExpectTrue(b bool) Condition {
-	return Condition{result: b, op: opTrue}
+	return expectBool(b, opTrue, "ExpectTrue")
 }
 
-// ExpectFalse returns a true Condition if the parameter is false.
+// ExpectFalse returns a true Condition if the parameter is false. The failure
+// message contains the source expression of the parameter if the source file
+// is available.
 func ExpectFalse(b bool) Condition {
-	return ExpectTrue(b).revert(opFalse)
+	return expectBool(!b, opFalse, "ExpectFalse")
+}
+
+// expectBool returns a Condition of the result, the call site of the function
+// fn is only captured when the result is false.
+func expectBool(result bool, op operator, fn string) Condition {
+	if result {
+		return newCondition(true, op)
+	}
+	return newCondition(false, op, callerSite(2, fn))
+}
+
+// newCondition returns a Condition of the result. The parameters are only kept
+// by a false Condition. They are kept in an array of the Condition, so values
+// passed by callers only escape to the heap if the Condition does.
+func newCondition(result bool, op operator, params ...any) Condition {
+	var c = Condition{result: result, op: op}
+	if !result {
+		// Elements are assigned one by one, copy would let them escape.
+		for i := range params {
+			c.params[i] = params[i]
+		}
+		c.n = len(params)
+	}
+	return c
+}
+
+// compare is the same as newCondition, but numbers are only converted to
+// interfaces by a false Condition.
+func compare[t number](result bool, op operator, a, b t) Condition {
+	if result {
+		return Condition{result: true, op: op}
+	}
+	return newCondition(false, op, a, b)
 }
 
 // Panicf panics with a formatted string.
@@ -346,42 +570,121 @@ func JustPanic() {
 	Panic("")
 }
 
+// maxParams is the maximum number of parameters of a Condition.
+const maxParams = 3
+
 // Condition supports to perform actions on expectation.
 type Condition struct {
 	result bool
 	op     operator
-	params []any
+	n      int
+	params [maxParams]any
+}
+
+// OK returns true if it is a true Condition.
+func (c Condition) OK() bool {
+	return c.result
+}
+
+// Message returns the generated failure message, or an empty string if it is
+// a true Condition.
+func (c Condition) Message() string {
+	if c.result {
+		return ""
+	}
+	return c.generateMessage()
+}
+
+// Op returns the stable name of the operator, e.g. "equal" or "in".
+func (c Condition) Op() string {
+	return c.op.String()
+}
+
+// Params returns the values checked by the Condition. Values are only kept by
+// a false Condition, so Params of a true Condition is empty. Conditions of
+// ExpectSame and ExpectNotSame keep the types of the values instead.
+func (c Condition) Params() []any {
+	return c.values()
+}
+
+// String implements fmt.Stringer.
+func (c Condition) String() string {
+	if c.result {
+		return c.op.String() + ": passed"
+	}
+	return c.op.String() + ": failed: " + c.generateMessage()
 }
 
 // Test will call Fail method if it is a false Condition. It is used while
-// testing, with *testing.T or *testing.B.
+// testing, with *testing.T or *testing.B. A FailureReport is appended as a
+// JSON line to the file in the environment variable XYCOND_REPORT if it is
+// set.
 func (c Condition) Test(f failer) {
 	if c.result {
 		return
 	}
-	var _, fn, ln, ok = runtime.Caller(1)
-	if ok {
+	var caller = externalCaller()
+	c.test(f, caller.File, caller.Line)
+}
+
+// test prints the message of the false Condition, which is tested at the
+// location, notifies failure hooks, and calls the Fail method. The failure is
+// only annotated if the test is marked as failed, failers which ignore
+// failures, e.g. mocks, are not annotated.
+func (c Condition) test(f failer, fn string, ln int) {
+	var msg = c.generateMessage()
+	if fn != "" {
 		fmt.Printf("%s:%d: ", fn, ln)
 	}
-	fmt.Println(c.generateMessage())
+	fmt.Println(msg)
+
+	var event = c.notify(f, msg, fn, ln)
 	f.Fail()
+	if t, ok := f.(interface{ Failed() bool }); ok && t.Failed() {
+		annotate(event)
+	}
+}
+
+// notify passes the failure of the test at the location to failure hooks and
+// the report file, and returns the event of the failure.
+func (c Condition) notify(f failer, msg, fn string, ln int) FailureEvent {
+	var event = c.newEvent(msg, fn, ln)
+	if n, ok := f.(namer); ok {
+		event.Test = n.Name()
+	}
+	globalHooks.call(event)
+	writeReport(event)
+	return event
 }
 
-// Assert prints the message and panics if it is a false Condition.
+// Assert handles a false Condition by the Policy set by SetPolicy, it panics
+// with the message by default. The generated message is used if msg is empty.
 func (c Condition) Assert(msg string) {
 	if !c.result {
-		if msg != "" {
-			Panic(msg)
-		} else {
-			Panic(c.generateMessage())
-		}
+		defaultAsserter.fail(context.Background(), c, msg)
 	}
 }
 
-// Assertf prints the format message and panics if it is a false Condition.
+// Assertf is the same as Assert, but with a format message.
 func (c Condition) Assertf(msg string, a ...any) {
 	if !c.result {
-		Panic(fmt.Sprintf(msg, a...))
+		defaultAsserter.fail(context.Background(), c, fmt.Sprintf(msg, a...))
+	}
+}
+
+// AssertContext is the same as Assert, but the Policy set by WithPolicy in the
+// context takes precedence over the Policy set by SetPolicy.
+func (c Condition) AssertContext(ctx context.Context, msg string) {
+	if !c.result {
+		defaultAsserter.fail(ctx, c, msg)
+	}
+}
+
+// must panics if it is a false Condition regardless of the Policy. It is used
+// to check arguments of functions in this package.
+func (c Condition) must() {
+	if !c.result {
+		Panic(c.generateMessage())
 	}
 }
 
@@ -401,38 +704,34 @@ func (c Condition) False(f func()) Condition {
 	return c
 }
 
-// revert returns the reverse Condition.
-func (c Condition) revert(op operator) Condition {
-	return Condition{result: !c.result, op: op, params: c.params}
-}
-
 func (c Condition) generateMessage() string {
 	switch c.op {
 	case opEqual:
-		return fmt.Sprintf("%v != %v", c.params[0], c.params[1])
+		return fmt.Sprintf("%s != %s", c.sprint(0), c.sprint(1))
 	case opNotEqual:
-		return fmt.Sprintf("got the same value (%v)", c.params[0])
+		return fmt.Sprintf("got the same value (%s)", c.sprint(0))
 	case opLessThan:
-		return fmt.Sprintf("%v is not less than %v", c.params[0], c.params[1])
+		return fmt.Sprintf("%s is not less than %s", c.sprint(0), c.sprint(1))
 	case opNotLessThan:
-		return fmt.Sprintf("%v is less than %v", c.params[0], c.params[1])
+		return fmt.Sprintf("%s is less than %s", c.sprint(0), c.sprint(1))
 	case opGreaterThan:
-		return fmt.Sprintf("%v is not greater than %v", c.params[0], c.params[1])
+		return fmt.Sprintf("%s is not greater than %s",
+			c.sprint(0), c.sprint(1))
 	case opNotGreaterThan:
-		return fmt.Sprintf("%v is greater than %v", c.params[0], c.params[1])
+		return fmt.Sprintf("%s is greater than %s", c.sprint(0), c.sprint(1))
 	case opPanic:
 		if c.params[0] == nil {
-			return fmt.Sprintf("expect no panic, but got %v", c.params[1])
+			return fmt.Sprintf("expect no panic, but got %s", c.sprint(1))
 		}
-		return fmt.Sprintf("expect a panic of %v, but got %v",
-			c.params[0], c.params[1])
+		return fmt.Sprintf("expect a panic of %s, but got %s",
+			c.sprint(0), c.sprint(1))
 	case opNil:
-		return fmt.Sprintf("expect a nil value, but got %v", c.params[0])
+		return fmt.Sprintf("expect a nil value, but got %s", c.sprint(0))
 	case opNotNil:
 		return "expect a not nil value, but got nil"
 	case opEmpty:
-		return fmt.Sprintf("expect a empty %s, but got %v",
-			c.params[1], c.params[0])
+		return fmt.Sprintf("expect a empty %s, but got %s",
+			c.params[1], c.sprint(0))
 	case opNotEmpty:
 		return fmt.Sprintf("expect a not empty %s, but got empty", c.params[1])
 	case opIs:
@@ -442,11 +741,9 @@ func (c Condition) generateMessage() string {
 		return fmt.Sprintf("expect a value not in %v, but got %v",
 			c.params[1], c.params[0])
 	case opSame:
-		var values = c.params[0].([]any)
 		var types []reflect.Type
-		for i := range values {
+		for _, vt := range c.params[0].([]reflect.Type) {
 			var diff = true
-			var vt = reflect.TypeOf(values[i])
 			for j := range types {
 				if vt == types[j] {
 					diff = false
@@ -459,10 +756,9 @@ func (c Condition) generateMessage() string {
 		return fmt.Sprintf("expect values to be the same type, but got %v",
 			types)
 	case opNotSame:
-		var values = c.params[0].([]any)
 		return fmt.Sprintf(
 			"expect values to be not the same type, but got only %v",
-			reflect.TypeOf(values[0]))
+			c.params[0].([]reflect.Type)[0])
 	case opWritable:
 		return "expect a wrtiable channel, but it's not"
 	case opNotWritable:
@@ -472,19 +768,122 @@ func (c Condition) generateMessage() string {
 	case opNotReadable:
 		return "expect not a readable channel, but it is"
 	case opError:
-		return fmt.Sprintf("expect a error in %v, but got %v",
-			c.params[1], c.params[0])
+		return fmt.Sprintf("expect a error in %s, but got %s",
+			c.sprint(1), c.sprint(0))
 	case opErrorNot:
-		return fmt.Sprintf("expect a error not in %v, but got %v",
-			c.params[1], c.params[0])
+		return fmt.Sprintf("expect a error not in %s, but got %s",
+			c.sprint(1), c.sprint(0))
 	case opIn:
-		return fmt.Sprintf("%v NOT IN %v", c.params[0], c.params[1])
+		return fmt.Sprintf("%s NOT IN %s", c.sprintElem(), c.sprint(1))
 	case opNotIn:
-		return fmt.Sprintf("%v IN %v", c.params[0], c.params[1])
+		return fmt.Sprintf("%s IN %s", c.sprintElem(), c.sprint(1))
 	case opTrue:
+		if expr := c.params[0].(callSite).expr(); expr != "" {
+			return fmt.Sprintf("expect %s to be true, but got false", expr)
+		}
 		return "expect true, but got false"
 	case opFalse:
+		if expr := c.params[0].(callSite).expr(); expr != "" {
+			return fmt.Sprintf("expect %s to be false, but got true", expr)
+		}
 		return "expect false, but got true"
+	case opSnapshot:
+		return fmt.Sprintf("snapshot %s: %s", c.params[0], c.params[1])
+	case opMatch:
+		return c.params[1].(Matcher).FailureMessage(c.params[0])
+	case opNotMatch:
+		return c.params[1].(Matcher).NegatedFailureMessage(c.params[0])
+	case opCustom:
+		if msg := c.params[0].(func() string); msg != nil {
+			return msg()
+		}
+		return "expect true, but got false"
+	case opRoundTrip:
+		if c.params[2] != nil {
+			return fmt.Sprintf("round trip of %s failed: %s",
+				c.sprint(0), c.params[2])
+		}
+		return fmt.Sprintf("expect %s after round trip, but got %s",
+			c.sprint(0), c.sprint(1))
+	case opNoPanic:
+		return fmt.Sprintf("expect no panic, but got %s\n%s",
+			c.sprint(0), c.params[1])
+	case opAllocsAtMost:
+		return fmt.Sprintf("expect at most %d allocations per run, but got %v",
+			c.params[0], c.params[1])
+	case opNoAllocs:
+		return fmt.Sprintf("expect no allocations, but got %v per run",
+			c.params[0])
+	case opFasterThan:
+		return fmt.Sprintf("expect a median time of %d runs less than %s, "+
+			"but got %s", c.params[2], c.params[0], c.params[1])
+	case opHeapGrowthAtMost:
+		return fmt.Sprintf("expect a heap growth of at most %d bytes, "+
+			"but got %d bytes", c.params[0], c.params[1])
+	case opFileExists:
+		return fmt.Sprintf("expect file %q to exist, but %s",
+			c.params[0], c.params[1])
+	case opDirExists:
+		return fmt.Sprintf("expect directory %q to exist, but %s",
+			c.params[0], c.params[1])
+	case opNoFile:
+		return fmt.Sprintf("expect no file %q, but %s",
+			c.params[0], c.params[1])
+	case opFileContent, opFileMode, opFileChecksum:
+		return fmt.Sprintf("file %q: %s", c.params[0], c.params[1])
+	case opTree:
+		return fmt.Sprintf("mismatched tree\n%s", c.params[0])
+	case opDone:
+		return fmt.Sprintf("expect the context to be done within %s, "+
+			"but it's not", c.params[0])
+	case opNotDone:
+		return fmt.Sprintf("expect the context not to be done, but got %s",
+			c.sprint(0))
+	case opCanceled:
+		switch {
+		case c.params[0] == nil:
+			return "expect the context to be canceled, but it's not done"
+		case !errors.Is(c.params[0].(error), context.Canceled):
+			return fmt.Sprintf("expect the context to be canceled, "+
+				"but got %s (cause: %s)", c.sprint(0), c.sprint(1))
+		}
+		return fmt.Sprintf("expect the context to be canceled by a cause "+
+			"in %s, but got %s", c.sprint(2), c.sprint(1))
+	case opDeadlineWithin:
+		if c.params[1] == nil {
+			return fmt.Sprintf("expect a deadline within %s, but got none",
+				c.params[0])
+		}
+		return fmt.Sprintf("expect a deadline within %s, but got %s",
+			c.params[0], c.params[1])
+	case opContextValue:
+		return fmt.Sprintf("expect %s of the context to be %s, but got %s",
+			c.sprint(0), c.sprint(1), c.sprint(2))
+	case opCompletesWithin:
+		return fmt.Sprintf("expect the function to complete within %s, "+
+			"but %s", c.params[0], c.params[1])
+	case opBlocks:
+		return fmt.Sprintf("expect the function to block for %s, "+
+			"but it returned after %s", c.params[0], c.params[1])
 	}
 	panic("no available operator")
 }
+
+// sprint pretty prints the i-th parameter.
+func (c Condition) sprint(i int) string {
+	return Sprint(c.params[i])
+}
+
+// sprintElem prints the element of ExpectIn, runes and bytes in a string are
+// printed as characters.
+func (c Condition) sprintElem() string {
+	if _, ok := c.params[1].(string); ok {
+		switch e := c.params[0].(type) {
+		case rune:
+			return strconv.QuoteRune(e)
+		case byte:
+			return strconv.QuoteRune(rune(e))
+		}
+	}
+	return c.sprint(0)
+}
@@ -28,10 +28,16 @@ import (
 	"github.com/xybor-x/xyerror"
 )
 
-type mocktest struct{}
+// mocktest is a test which ignores failures, its name is used by snapshots
+// and reports.
+type mocktest struct {
+	name string
+}
 
 func (mocktest) Fail() {}
 
+func (m mocktest) Name() string { return m.name }
+
 func TestCondition(t *testing.T) {
 	xycond.ExpectTrue(false).Test(mocktest{})
 
@@ -143,3 +149,23 @@ func TestPanic(t *testing.T) {
 		xycond.JustPanic()
 	}).Test(t)
 }
+
+func TestConditionAccessors(t *testing.T) {
+	var c = xycond.ExpectIn(3, []int{1, 2})
+	xycond.ExpectFalse(c.OK()).Test(t)
+	xycond.ExpectEqual(c.Op(), "in").Test(t)
+	xycond.ExpectEqual(c.Message(), "3 NOT IN []int{1, 2}").Test(t)
+	xycond.ExpectEqual(len(c.Params()), 2).Test(t)
+	xycond.ExpectEqual(c.Params()[0], 3).Test(t)
+	xycond.ExpectEqual(c.String(), "in: failed: 3 NOT IN []int{1, 2}").Test(t)
+
+	c = xycond.ExpectEqual(1, 1)
+	xycond.ExpectTrue(c.OK()).Test(t)
+	xycond.ExpectEqual(c.Op(), "equal").Test(t)
+	xycond.ExpectEqual(c.Message(), "").Test(t)
+	xycond.ExpectEqual(c.String(), "equal: passed").Test(t)
+
+	c = xycond.ExpectTrue(false)
+	xycond.ExpectEqual(c.Op(), "true").Test(t)
+	xycond.ExpectEqual(len(c.Params()), 0).Test(t)
+}
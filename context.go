@@ -0,0 +1,90 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// ExpectDone returns a true Condition if the context is done within the
+// duration.
+func ExpectDone(ctx context.Context, within time.Duration) Condition {
+	var timer = time.NewTimer(within)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return newCondition(true, opDone, within)
+	case <-timer.C:
+		return newCondition(false, opDone, within)
+	}
+}
+
+// ExpectNotDone returns a true Condition if the context is not done yet.
+func ExpectNotDone(ctx context.Context) Condition {
+	select {
+	case <-ctx.Done():
+		return newCondition(false, opNotDone, context.Cause(ctx))
+	default:
+		return newCondition(true, opNotDone)
+	}
+}
+
+// ExpectCanceled returns a true Condition if the context is canceled. If
+// causes are passed, the cause of the context, see context.Cause, must also
+// belong to one of them.
+func ExpectCanceled(ctx context.Context, causes ...error) Condition {
+	var err = ctx.Err()
+	var cause = context.Cause(ctx)
+	var result = errors.Is(err, context.Canceled)
+	if result && len(causes) > 0 {
+		result = false
+		for i := range causes {
+			if errors.Is(cause, causes[i]) {
+				result = true
+			}
+		}
+	}
+	return newCondition(result, opCanceled, err, cause, causes)
+}
+
+// ExpectDeadlineWithin returns a true Condition if the context has a deadline
+// which is at most the duration from now.
+func ExpectDeadlineWithin(ctx context.Context, d time.Duration) Condition {
+	var deadline, ok = ctx.Deadline()
+	if !ok {
+		return newCondition(false, opDeadlineWithin, d, nil)
+	}
+
+	var remaining = time.Until(deadline)
+	return newCondition(remaining <= d, opDeadlineWithin, d, remaining)
+}
+
+// ExpectContextValue returns a true Condition if the value of the key in the
+// context is deeply equal to the wanted value.
+func ExpectContextValue(ctx context.Context, key, want any) Condition {
+	var got = ctx.Value(key)
+	return newCondition(reflect.DeepEqual(got, want), opContextValue,
+		key, want, got)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xybor-x/xycond"
+)
+
+type ctxKey string
+
+func TestExpectDone(t *testing.T) {
+	var ctx, cancel = context.WithCancel(context.Background())
+	xycond.ExpectNotDone(ctx).Test(t)
+	xycond.ExpectEqual(xycond.ExpectDone(ctx, time.Millisecond).Message(),
+		"expect the context to be done within 1ms, but it's not").Test(t)
+
+	time.AfterFunc(time.Millisecond, cancel)
+	xycond.ExpectDone(ctx, time.Second).Test(t)
+	xycond.ExpectEqual(xycond.ExpectNotDone(ctx).Message(),
+		"expect the context not to be done, but got context canceled").Test(t)
+}
+
+func TestExpectCanceled(t *testing.T) {
+	var errStop = errors.New("stop")
+	var ctx, cancel = context.WithCancelCause(context.Background())
+	xycond.ExpectEqual(xycond.ExpectCanceled(ctx).Message(),
+		"expect the context to be canceled, but it's not done").Test(t)
+
+	cancel(errStop)
+	xycond.ExpectCanceled(ctx).Test(t)
+	xycond.ExpectCanceled(ctx, errStop).Test(t)
+	xycond.ExpectEqual(
+		xycond.ExpectCanceled(ctx, context.DeadlineExceeded).Message(),
+		"expect the context to be canceled by a cause in "+
+			"[]error{context deadline exceeded}, but got stop").Test(t)
+
+	var timeout, stop = context.WithTimeoutCause(
+		context.Background(), 0, errStop)
+	defer stop()
+	xycond.ExpectEqual(xycond.ExpectCanceled(timeout).Message(),
+		"expect the context to be canceled, "+
+			"but got context deadline exceeded (cause: stop)").Test(t)
+}
+
+func TestExpectDeadlineWithin(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	xycond.ExpectDeadlineWithin(ctx, time.Minute).Test(t)
+	xycond.ExpectTrue(strings.HasPrefix(
+		xycond.ExpectDeadlineWithin(ctx, time.Second).Message(),
+		"expect a deadline within 1s, but got 59.")).Test(t)
+	xycond.ExpectEqual(
+		xycond.ExpectDeadlineWithin(context.Background(), time.Second).
+			Message(),
+		"expect a deadline within 1s, but got none").Test(t)
+}
+
+func TestExpectContextValue(t *testing.T) {
+	var ctx = context.WithValue(context.Background(), ctxKey("user"),
+		[]string{"alice"})
+
+	xycond.ExpectContextValue(ctx, ctxKey("user"), []string{"alice"}).Test(t)
+	xycond.ExpectContextValue(ctx, ctxKey("role"), nil).Test(t)
+	xycond.ExpectEqual(
+		xycond.ExpectContextValue(ctx, ctxKey("user"), "bob").Message(),
+		`expect xycond_test.ctxKey("user") of the context to be "bob", `+
+			`but got []string{"alice"}`).Test(t)
+}
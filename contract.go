@@ -0,0 +1,116 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/xybor-x/xyerror"
+)
+
+var (
+	// ContractError is the base class of contract violations.
+	ContractError = xyerror.AssertionError.NewException("ContractError")
+
+	// PreconditionError is raised by Require, it is also a ValueError because
+	// a violated precondition is a bug of the caller.
+	PreconditionError = xyerror.Combine(
+		ContractError, xyerror.ValueError,
+	).NewException("PreconditionError")
+
+	// PostconditionError is raised by Ensure, a violated postcondition is a
+	// bug of the implementation.
+	PostconditionError = ContractError.NewException("PostconditionError")
+
+	// InvariantError is raised by CheckInvariant.
+	InvariantError = ContractError.NewException("InvariantError")
+)
+
+// Invariant is implemented by types whose invariant can be checked.
+type Invariant interface {
+	// CheckInvariant returns a true Condition if the invariant holds.
+	CheckInvariant() Condition
+}
+
+// Require panics with a PreconditionError if the Condition is false. The
+// description names the precondition, e.g. "param x". Contracts panic
+// regardless of the Policy.
+func Require(c Condition, desc string) {
+	if !c.result {
+		violate(c, PreconditionError, desc+": "+c.generateMessage())
+	}
+}
+
+// Ensure panics with a PostconditionError if the Condition returned by the
+// function is false. It is deferred at the beginning of a function, so the
+// function can inspect named results, e.g.
+//
+//	defer xycond.Ensure(func() xycond.Condition {
+//		return xycond.ExpectNotNil(result)
+//	})
+//
+// The postcondition is only checked if the function returns normally, Ensure
+// must be deferred directly for that, not in a closure.
+func Ensure(f func() Condition) {
+	if unwinding() {
+		return
+	}
+	if c := f(); !c.result {
+		violate(c, PostconditionError, c.generateMessage())
+	}
+}
+
+// CheckInvariant panics with an InvariantError if the invariant of the object
+// doesn't hold. It is called at the entry of a method, or deferred directly to
+// be called at the exit, in which case the invariant is only checked if the
+// method returns normally.
+func CheckInvariant(obj Invariant) {
+	if unwinding() {
+		return
+	}
+	if c := obj.CheckInvariant(); !c.result {
+		violate(c, InvariantError,
+			fmt.Sprintf("%T: %s", obj, c.generateMessage()))
+	}
+}
+
+// violate notifies failure hooks of the violated contract and panics with an
+// error of the class.
+func violate(c Condition, class xyerror.Exception, msg string) {
+	var caller = externalCaller()
+	var event = c.newEvent(msg, caller.File, caller.Line)
+	globalHooks.call(event)
+	annotatePanic(event)
+	panic(class.New(msg))
+}
+
+// unwinding returns true if the caller is deferred by a function which is
+// panicking or calling runtime.Goexit. In these cases the deferred call is
+// made by the runtime, instead of the function itself when it returns.
+func unwinding() bool {
+	var pcs [8]uintptr
+	var frames = runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	frames.Next()
+	var frame, _ = frames.Next()
+	return strings.HasPrefix(frame.Function, "runtime.")
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"errors"
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+	"github.com/xybor-x/xyerror"
+)
+
+type stack struct {
+	items []int
+	size  int
+}
+
+func (s *stack) CheckInvariant() xycond.Condition {
+	return xycond.ExpectEqual(len(s.items), s.size)
+}
+
+func (s *stack) push(v int, broken bool) {
+	xycond.CheckInvariant(s)
+	defer xycond.CheckInvariant(s)
+
+	s.items = append(s.items, v)
+	if !broken {
+		s.size++
+	}
+}
+
+func sqrt(n int, broken bool) (result int) {
+	xycond.Require(xycond.ExpectNotLessThan(n, 0), "param n")
+	defer xycond.Ensure(func() xycond.Condition {
+		return xycond.ExpectNotGreaterThan(result*result, n)
+	})
+
+	for result*result <= n {
+		result++
+	}
+	if broken {
+		return result
+	}
+	return result - 1
+}
+
+func expectContractError(t *testing.T, class error, msg string, f func()) {
+	defer func() {
+		var err, _ = recover().(error)
+		xycond.ExpectError(err, class).Test(t)
+		xycond.ExpectError(err, xycond.ContractError).Test(t)
+		xycond.ExpectError(err, xyerror.AssertionError).Test(t)
+		xycond.ExpectEqual(err.Error(), msg).Test(t)
+	}()
+	f()
+}
+
+func TestRequire(t *testing.T) {
+	xycond.ExpectEqual(sqrt(10, false), 3).Test(t)
+
+	expectContractError(t, xycond.PreconditionError,
+		"PreconditionError: param n: -1 is less than 0", func() {
+			sqrt(-1, false)
+		})
+	expectContractError(t, xyerror.ValueError,
+		"PreconditionError: param n: -1 is less than 0", func() {
+			sqrt(-1, false)
+		})
+}
+
+func TestEnsure(t *testing.T) {
+	expectContractError(t, xycond.PostconditionError,
+		"PostconditionError: 16 is greater than 10", func() {
+			sqrt(10, true)
+		})
+
+	var errFoo = errors.New("foo")
+	xycond.ExpectPanic(errFoo, func() {
+		defer xycond.Ensure(func() xycond.Condition {
+			return xycond.ExpectTrue(false)
+		})
+		panic(errFoo)
+	}).Test(t)
+}
+
+func TestEnsurePanicking(t *testing.T) {
+	var stack string
+	func() {
+		defer func() {
+			recover()
+			stack = string(debug.Stack())
+		}()
+		defer xycond.Ensure(func() xycond.Condition {
+			t.Error("the postcondition is checked while panicking")
+			return xycond.ExpectTrue(true)
+		})
+		panic("foo")
+	}()
+
+	// The panic is not recovered and raised again by Ensure.
+	xycond.ExpectNotIn("xycond.Ensure", stack).Test(t)
+}
+
+func TestEnsureGoexit(t *testing.T) {
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		defer xycond.Ensure(func() xycond.Condition {
+			t.Error("the postcondition is checked while exiting")
+			return xycond.ExpectTrue(true)
+		})
+		runtime.Goexit()
+	}()
+	<-done
+}
+
+func TestCheckInvariant(t *testing.T) {
+	var s = &stack{}
+	s.push(1, false)
+
+	expectContractError(t, xycond.InvariantError,
+		"InvariantError: *xycond_test.stack: 2 != 1", func() {
+			s.push(2, true)
+		})
+
+	xycond.ExpectErrorNot(
+		xyerror.AssertionError.New(""), xycond.ContractError).Test(t)
+	xycond.ExpectErrorNot(
+		xycond.InvariantError.New(""), xyerror.ValueError).Test(t)
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import "reflect"
+
+// Enabled returns false if the package is built with the xycond_release tag.
+//
+// Debug functions are the same as Assert functions, but they do nothing in
+// release builds. Their arguments are still evaluated unless the compiler can
+// prove they have no side effects, so expensive arguments should be guarded by
+// Enabled, the guarded code is removed by the compiler in release builds.
+//
+//	if xycond.Enabled() {
+//		xycond.DebugTrue(isSorted(items))
+//	}
+func Enabled() bool {
+	return enabled
+}
+
+// DebugEqual panics if a is different from b.
+func DebugEqual(a, b any) {
+	if enabled {
+		ExpectEqual(a, b).Assert("")
+	}
+}
+
+// DebugNotEqual panics if a is equal to b.
+func DebugNotEqual(a, b any) {
+	if enabled {
+		ExpectNotEqual(a, b).Assert("")
+	}
+}
+
+// DebugEq panics if a is different from b, it doesn't allocate if it passes.
+func DebugEq[T comparable](a, b T) {
+	if enabled && a != b {
+		ExpectEqual(a, b).Assert("")
+	}
+}
+
+// DebugNotEq panics if a is equal to b, it doesn't allocate if it passes.
+func DebugNotEq[T comparable](a, b T) {
+	if enabled && a == b {
+		ExpectNotEqual(a, b).Assert("")
+	}
+}
+
+// DebugLessThan panics if a is not less than b.
+func DebugLessThan[t number](a, b t) {
+	if enabled {
+		ExpectLessThan(a, b).Assert("")
+	}
+}
+
+// DebugNotLessThan panics if a is less than b.
+func DebugNotLessThan[t number](a, b t) {
+	if enabled {
+		ExpectNotLessThan(a, b).Assert("")
+	}
+}
+
+// DebugGreaterThan panics if a is not greater than b.
+func DebugGreaterThan[t number](a, b t) {
+	if enabled {
+		ExpectGreaterThan(a, b).Assert("")
+	}
+}
+
+// DebugNotGreaterThan panics if a is greater than b.
+func DebugNotGreaterThan[t number](a, b t) {
+	if enabled {
+		ExpectNotGreaterThan(a, b).Assert("")
+	}
+}
+
+// DebugPanic panics if the function doesn't panic.
+func DebugPanic(r any, f func()) {
+	if enabled {
+		ExpectPanic(r, f).Assert("")
+	}
+}
+
+// DebugZero panics if the parameter is not zero.
+func DebugZero[t number](a t) {
+	if enabled {
+		ExpectZero(a).Assert("")
+	}
+}
+
+// DebugNotZero panics if the parameter is zero.
+func DebugNotZero[t number](a t) {
+	if enabled {
+		ExpectNotZero(a).Assert("")
+	}
+}
+
+// DebugNil panics if the parameter is not nil.
+func DebugNil(a any) {
+	if enabled {
+		ExpectNil(a).Assert("")
+	}
+}
+
+// DebugNotNil panics if the parameter is nil.
+func DebugNotNil(a any) {
+	if enabled {
+		ExpectNotNil(a).Assert("")
+	}
+}
+
+// DebugEmpty panics if the parameter is not empty.
+func DebugEmpty(a any) {
+	if enabled {
+		ExpectEmpty(a).Assert("")
+	}
+}
+
+// DebugNotEmpty panics if the parameter is empty.
+func DebugNotEmpty(a any) {
+	if enabled {
+		ExpectNotEmpty(a).Assert("")
+	}
+}
+
+// DebugIs panics if value doesn't belongs to any passed kinds.
+func DebugIs(v any, kinds ...reflect.Kind) {
+	if enabled {
+		ExpectIs(v, kinds...).Assert("")
+	}
+}
+
+// DebugIsNot panics if value belongs to one of passed kinds.
+func DebugIsNot(v any, kinds ...reflect.Kind) {
+	if enabled {
+		ExpectIsNot(v, kinds...).Assert("")
+	}
+}
+
+// DebugSame panics if there is at least value' type different from the rest.
+func DebugSame(v ...any) {
+	if enabled {
+		ExpectSame(v...).Assert("")
+	}
+}
+
+// DebugNotSame panics if all values' type are the same.
+func DebugNotSame(v ...any) {
+	if enabled {
+		ExpectNotSame(v...).Assert("")
+	}
+}
+
+// DebugWritable panics if the parameter is not a writable channel.
+func DebugWritable(c any) {
+	if enabled {
+		ExpectWritable(c).Assert("")
+	}
+}
+
+// DebugNotWritable panics if the parameter is a writable channel.
+func DebugNotWritable(c any) {
+	if enabled {
+		ExpectNotWritable(c).Assert("")
+	}
+}
+
+// DebugReadable panics if the parameter is not a readable channel.
+func DebugReadable(c any) {
+	if enabled {
+		ExpectReadable(c).Assert("")
+	}
+}
+
+// DebugNotReadable panics if the parameter is a readable channel.
+func DebugNotReadable(c any) {
+	if enabled {
+		ExpectNotReadable(c).Assert("")
+	}
+}
+
+// DebugError panics if the err doesn't belong to any targets.
+func DebugError(err error, targets ...error) {
+	if enabled {
+		ExpectError(err, targets...).Assert("")
+	}
+}
+
+// DebugErrorNot panics if the err belongs to one of targets.
+func DebugErrorNot(err error, targets ...error) {
+	if enabled {
+		ExpectErrorNot(err, targets...).Assert("")
+	}
+}
+
+// DebugIn panics if the element is not in the object. The object must be an
+// array, slice, string, or map.
+func DebugIn(element any, object any) {
+	if enabled {
+		ExpectIn(element, object).Assert("")
+	}
+}
+
+// DebugNotIn panics if the element is in the object. The object must be an
+// array, slice, string, or map.
+func DebugNotIn(element any, object any) {
+	if enabled {
+		ExpectNotIn(element, object).Assert("")
+	}
+}
+
+// DebugTrue panics if the condition is false.
+func DebugTrue(b bool) {
+	if enabled {
+		expectBool(b, opTrue, "DebugTrue").Assert("")
+	}
+}
+
+// DebugFalse panics if the condition is true.
+func DebugFalse(b bool) {
+	if enabled {
+		expectBool(!b, opFalse, "DebugFalse").Assert("")
+	}
+}
+
+// Debug panics if the actual value doesn't match the Matcher.
+func Debug(actual any, m Matcher) {
+	if enabled {
+		Expect(actual, m).Assert("")
+	}
+}
+
+// DebugNot panics if the actual value matches the Matcher.
+func DebugNot(actual any, m Matcher) {
+	if enabled {
+		ExpectNot(actual, m).Assert("")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !xycond_release
+
+package xycond_test
+
+import (
+	"testing"
+
+	"github.com/xybor-x/xycond"
+	"github.com/xybor-x/xyerror"
+)
+
+func TestDebug(t *testing.T) {
+	xycond.ExpectTrue(xycond.Enabled()).Test(t)
+
+	xycond.DebugEqual(1, 1)
+	xycond.DebugEq(1, 1)
+	xycond.DebugNotEq(1, 2)
+	xycond.DebugLessThan(1, 2)
+	xycond.DebugIn(1, []int{1})
+	xycond.DebugTrue(true)
+
+	xycond.ExpectPanic(xyerror.AssertionError, func() {
+		xycond.DebugEqual(1, 2)
+	}).Test(t)
+	expectPanicMessage(t, "1 != 2", func() {
+		xycond.DebugEq(1, 2)
+	})
+	expectPanicMessage(t, "expect 1 > 2 to be true, but got false", func() {
+		xycond.DebugTrue(1 > 2)
+	})
+}
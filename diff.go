@@ -0,0 +1,284 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines surrounding a change in a hunk.
+const diffContext = 3
+
+type diffKind int
+
+const (
+	diffEqual diffKind = iota
+	diffDelete
+	diffInsert
+)
+
+type diffLine struct {
+	kind diffKind
+	text string
+}
+
+// unifiedDiff returns the unified diff between two texts, or an empty string
+// if they are the same.
+func unifiedDiff(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+
+	var lines = diffLines(splitLines(from), splitLines(to))
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	// aLine and bLine are the 1-based line numbers of lines[i] in both texts.
+	var aLine, bLine = 1, 1
+	var hunks = 0
+	for i := 0; i < len(lines); {
+		if lines[i].kind == diffEqual {
+			aLine++
+			bLine++
+			i++
+			continue
+		}
+
+		// Expand the hunk backward and forward to include the context lines,
+		// merging changes which are closer than twice the context.
+		var start = i
+		var back = 0
+		for start > 0 && back < diffContext &&
+			lines[start-1].kind == diffEqual {
+			start--
+			back++
+		}
+
+		var end = i
+		for end < len(lines) {
+			if lines[end].kind != diffEqual {
+				end++
+				continue
+			}
+			var run = end
+			for run < len(lines) && lines[run].kind == diffEqual {
+				run++
+			}
+			if run == len(lines) || run-end > 2*diffContext {
+				if run-end > diffContext {
+					run = end + diffContext
+				}
+				end = run
+				break
+			}
+			end = run
+		}
+
+		var aStart, bStart = aLine - back, bLine - back
+		var aCount, bCount = 0, 0
+		var body strings.Builder
+		for _, l := range lines[start:end] {
+			switch l.kind {
+			case diffEqual:
+				aCount++
+				bCount++
+				body.WriteString(" ")
+			case diffDelete:
+				aCount++
+				body.WriteString("-")
+			case diffInsert:
+				bCount++
+				body.WriteString("+")
+			}
+			body.WriteString(l.text)
+			body.WriteString("\n")
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		sb.WriteString(body.String())
+		hunks++
+
+		for _, l := range lines[i:end] {
+			if l.kind != diffInsert {
+				aLine++
+			}
+			if l.kind != diffDelete {
+				bLine++
+			}
+		}
+		i = end
+	}
+
+	if hunks == 0 {
+		sb.WriteString("\\ Texts differ only in the trailing newline\n")
+	}
+
+	return sb.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits the text into lines, the trailing newline doesn't produce
+// an empty line.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes the shortest edit script between a and b by using the
+// linear space variant of the Myers algorithm, so large texts don't need a
+// table of the product of their lengths.
+func diffLines(a, b []string) []diffLine {
+	var d = differ{a: a, b: b}
+	d.diff(0, len(a), 0, len(b))
+	return d.result
+}
+
+type differ struct {
+	a, b   []string
+	result []diffLine
+}
+
+// diff appends the edit script between a[aLo:aHi] and b[bLo:bHi].
+func (d *differ) diff(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.result = append(d.result, diffLine{diffEqual, d.a[aLo]})
+		aLo++
+		bLo++
+	}
+	var suffix = 0
+	for aLo < aHi-suffix && bLo < bHi-suffix &&
+		d.a[aHi-1-suffix] == d.b[bHi-1-suffix] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+
+	if x, y, ok := d.middle(aLo, aHi, bLo, bHi); ok {
+		d.diff(aLo, x, bLo, y)
+		d.diff(x, aHi, y, bHi)
+	} else {
+		for _, l := range d.a[aLo:aHi] {
+			d.result = append(d.result, diffLine{diffDelete, l})
+		}
+		for _, l := range d.b[bLo:bHi] {
+			d.result = append(d.result, diffLine{diffInsert, l})
+		}
+	}
+
+	for _, l := range d.a[aHi : aHi+suffix] {
+		d.result = append(d.result, diffLine{diffEqual, l})
+	}
+}
+
+// middle finds the point where the forward and the reverse shortest paths
+// between a[aLo:aHi] and b[bLo:bHi] overlap. It returns false if the ranges
+// have nothing in common, so they are only deleted and inserted.
+func (d *differ) middle(aLo, aHi, bLo, bHi int) (int, int, bool) {
+	var n, m = aHi - aLo, bHi - bLo
+	if n == 0 || m == 0 {
+		return 0, 0, false
+	}
+
+	// vf and vr keep the furthest x of the forward and the reverse paths on
+	// each diagonal k = x - y, shifted by limit.
+	var limit = (n + m + 1) / 2
+	var vf, vr = make([]int, 2*limit+2), make([]int, 2*limit+2)
+	for i := range vf {
+		vf[i], vr[i] = -1, -1
+	}
+	vf[limit+1], vr[limit+1] = 0, 0
+
+	var delta = n - m
+	var front = delta%2 != 0
+	var fStart, fEnd, rStart, rEnd = 0, 0, 0, 0
+	for e := 0; e < limit; e++ {
+		for k := -e + fStart; k <= e-fEnd; k += 2 {
+			var i = limit + k
+			var x int
+			if k == -e || (k != e && vf[i-1] < vf[i+1]) {
+				x = vf[i+1]
+			} else {
+				x = vf[i-1] + 1
+			}
+			var y = x - k
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			vf[i] = x
+
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case front:
+				var j = limit + delta - k
+				if j >= 0 && j < len(vr) && vr[j] != -1 && x >= n-vr[j] {
+					return aLo + x, bLo + y, true
+				}
+			}
+		}
+
+		for k := -e + rStart; k <= e-rEnd; k += 2 {
+			var i = limit + k
+			var x int
+			if k == -e || (k != e && vr[i-1] < vr[i+1]) {
+				x = vr[i+1]
+			} else {
+				x = vr[i-1] + 1
+			}
+			var y = x - k
+			for x < n && y < m && d.a[aHi-1-x] == d.b[bHi-1-y] {
+				x++
+				y++
+			}
+			vr[i] = x
+
+			switch {
+			case x > n:
+				rEnd += 2
+			case y > m:
+				rStart += 2
+			case !front:
+				var j = limit + delta - k
+				if j >= 0 && j < len(vf) && vf[j] != -1 && vf[j] >= n-x {
+					var fx = vf[j]
+					return aLo + fx, bLo + fx - (j - limit), true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// ExpectFileExists returns a true Condition if the name is a regular file in
+// the file system.
+func ExpectFileExists(fsys fs.FS, name string) Condition {
+	var detail string
+	var info, err = fs.Stat(fsys, name)
+	switch {
+	case err != nil:
+		detail = statDetail(err)
+	case !info.Mode().IsRegular():
+		detail = "got a " + fileKind(info)
+	}
+	return newCondition(detail == "", opFileExists, name, detail)
+}
+
+// ExpectDirExists returns a true Condition if the name is a directory in the
+// file system.
+func ExpectDirExists(fsys fs.FS, name string) Condition {
+	var detail string
+	var info, err = fs.Stat(fsys, name)
+	switch {
+	case err != nil:
+		detail = statDetail(err)
+	case !info.IsDir():
+		detail = "got a " + fileKind(info)
+	}
+	return newCondition(detail == "", opDirExists, name, detail)
+}
+
+// ExpectNoFile returns a true Condition if nothing exists at the name in the
+// file system. Errors other than fs.ErrNotExist, e.g. a denied permission,
+// make a false Condition.
+func ExpectNoFile(fsys fs.FS, name string) Condition {
+	var detail string
+	var info, err = fs.Stat(fsys, name)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return newCondition(true, opNoFile, name)
+	case err != nil:
+		detail = statDetail(err)
+	default:
+		detail = "got a " + fileKind(info)
+	}
+	return newCondition(false, opNoFile, name, detail)
+}
+
+// ExpectFileContent returns a true Condition if the file has the content. The
+// failure message contains the unified diff between the contents.
+func ExpectFileContent(fsys fs.FS, name string, want string) Condition {
+	var detail string
+	var got, err = fs.ReadFile(fsys, name)
+	switch {
+	case err != nil:
+		detail = statDetail(err)
+	case string(got) != want:
+		detail = "mismatched content\n" +
+			unifiedDiff("want", name, want, string(got))
+	}
+	return newCondition(detail == "", opFileContent, name, detail)
+}
+
+// ExpectFileMode returns a true Condition if the file has the permission bits
+// of the mode. Type bits, e.g. fs.ModeDir, are also compared if the mode has
+// any.
+func ExpectFileMode(fsys fs.FS, name string, mode fs.FileMode) Condition {
+	var detail string
+	var info, err = fs.Stat(fsys, name)
+	if err != nil {
+		detail = statDetail(err)
+	} else if got := info.Mode() & (fs.ModePerm | mode.Type()); got != mode {
+		detail = fmt.Sprintf("expect mode %s, but got %s", mode, got)
+	}
+	return newCondition(detail == "", opFileMode, name, detail)
+}
+
+// ExpectFileChecksum returns a true Condition if the hex-encoded SHA-256
+// checksum of the file is the sum.
+func ExpectFileChecksum(fsys fs.FS, name string, sum string) Condition {
+	var detail string
+	var data, err = fs.ReadFile(fsys, name)
+	var got = sha256.Sum256(data)
+	switch {
+	case err != nil:
+		detail = statDetail(err)
+	case !strings.EqualFold(hex.EncodeToString(got[:]), sum):
+		detail = fmt.Sprintf("expect sha256 %s, but got %x", sum, got)
+	}
+	return newCondition(detail == "", opFileChecksum, name, detail)
+}
+
+// ExpectTree returns a true Condition if the file system has the same layout
+// as the spec, which is usually an fstest.MapFS or an os.DirFS of a golden
+// directory. Missing and extra files or directories are reported, and regular
+// files with different contents are reported with their diffs.
+func ExpectTree(fsys fs.FS, spec fs.FS) Condition {
+	var got, err = walkTree(fsys)
+	if err == nil {
+		var want map[string]fs.FileMode
+		want, err = walkTree(spec)
+		if err == nil {
+			var report = compareTrees(fsys, spec, got, want)
+			if report == "" {
+				return newCondition(true, opTree)
+			}
+			return newCondition(false, opTree, report)
+		}
+	}
+	return newCondition(false, opTree, err.Error())
+}
+
+// walkTree returns types of all files in the file system except its root.
+func walkTree(fsys fs.FS) (map[string]fs.FileMode, error) {
+	var tree = map[string]fs.FileMode{}
+	var err = fs.WalkDir(fsys, ".",
+		func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if path != "." {
+				tree[path] = d.Type()
+			}
+			return nil
+		})
+	return tree, err
+}
+
+func compareTrees(
+	fsys, spec fs.FS,
+	got, want map[string]fs.FileMode,
+) string {
+	var paths []string
+	for path := range got {
+		paths = append(paths, path)
+	}
+	for path := range want {
+		if _, ok := got[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var sb strings.Builder
+	for _, path := range paths {
+		var gotType, inGot = got[path]
+		var wantType, inWant = want[path]
+		switch {
+		case !inGot:
+			fmt.Fprintf(&sb, "missing: %s\n", path)
+		case !inWant:
+			fmt.Fprintf(&sb, "extra: %s\n", path)
+		case gotType != wantType:
+			fmt.Fprintf(&sb, "different type: %s\n", path)
+		case gotType.IsRegular():
+			var gotData, err1 = fs.ReadFile(fsys, path)
+			var wantData, err2 = fs.ReadFile(spec, path)
+			if err := errors.Join(err1, err2); err != nil {
+				fmt.Fprintf(&sb, "unreadable: %s: %s\n", path, err)
+			} else if string(gotData) != string(wantData) {
+				fmt.Fprintf(&sb, "different: %s\n%s", path,
+					unifiedDiff("want", path, string(wantData),
+						string(gotData)))
+			}
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// statDetail describes the error of accessing a file.
+func statDetail(err error) string {
+	if errors.Is(err, fs.ErrNotExist) {
+		return "it doesn't exist"
+	}
+	return err.Error()
+}
+
+// fileKind describes the type of the file.
+func fileKind(info fs.FileInfo) string {
+	switch {
+	case info.IsDir():
+		return "directory"
+	case info.Mode().IsRegular():
+		return "regular file"
+	case info.Mode()&fs.ModeSymlink != 0:
+		return "symbolic link"
+	}
+	return "special file"
+}
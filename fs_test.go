@@ -0,0 +1,172 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xybor-x/xycond"
+)
+
+var testFS = fstest.MapFS{
+	"cmd/main.go": {Data: []byte("package main\n"), Mode: 0o644},
+	"run.sh":      {Data: []byte("#!/bin/sh\n"), Mode: 0o755},
+	"docs":        {Mode: fs.ModeDir | 0o755},
+}
+
+func TestExpectFileExists(t *testing.T) {
+	xycond.ExpectFileExists(testFS, "cmd/main.go").Test(t)
+	xycond.ExpectDirExists(testFS, "cmd").Test(t)
+	xycond.ExpectDirExists(testFS, "docs").Test(t)
+	xycond.ExpectNoFile(testFS, "cmd/main_test.go").Test(t)
+	xycond.ExpectEmpty(xycond.ExpectFileExists(testFS, "run.sh").Params()).
+		Test(t)
+
+	xycond.ExpectEqual(xycond.ExpectFileExists(testFS, "cmd").Message(),
+		`expect file "cmd" to exist, but got a directory`).Test(t)
+	xycond.ExpectEqual(xycond.ExpectFileExists(testFS, "foo").Message(),
+		`expect file "foo" to exist, but it doesn't exist`).Test(t)
+	xycond.ExpectEqual(xycond.ExpectDirExists(testFS, "run.sh").Message(),
+		`expect directory "run.sh" to exist, but got a regular file`).Test(t)
+	xycond.ExpectEqual(xycond.ExpectNoFile(testFS, "docs").Message(),
+		`expect no file "docs", but got a directory`).Test(t)
+
+	var c = xycond.ExpectNoFile(deniedFS{}, "secret")
+	xycond.ExpectFalse(c.OK()).Test(t)
+	xycond.ExpectEqual(c.Message(),
+		`expect no file "secret", but open secret: permission denied`).
+		Test(t)
+}
+
+// deniedFS denies opening any file.
+type deniedFS struct{}
+
+func (deniedFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+}
+
+func TestExpectFileContent(t *testing.T) {
+	xycond.ExpectFileContent(testFS, "run.sh", "#!/bin/sh\n").Test(t)
+
+	var c = xycond.ExpectFileContent(testFS, "run.sh", "#!/bin/bash\n")
+	xycond.ExpectEqual(c.Message(), `file "run.sh": mismatched content
+--- want
++++ run.sh
+@@ -1 +1 @@
+-#!/bin/bash
++#!/bin/sh
+`).Test(t)
+}
+
+func TestExpectFileContentLarge(t *testing.T) {
+	var want, got strings.Builder
+	for i := 0; i < 100000; i++ {
+		fmt.Fprintf(&want, "line %d\n", i)
+		if i%50000 == 49999 {
+			fmt.Fprintf(&got, "line 0\n")
+		} else {
+			fmt.Fprintf(&got, "line %d\n", i)
+		}
+	}
+	var fsys = fstest.MapFS{"large": {Data: []byte(got.String())}}
+
+	var c = xycond.ExpectFileContent(fsys, "large", want.String())
+	xycond.ExpectEqual(c.Message(), `file "large": mismatched content
+--- want
++++ large
+@@ -49997,7 +49997,7 @@
+ line 49996
+ line 49997
+ line 49998
+-line 49999
++line 0
+ line 50000
+ line 50001
+ line 50002
+@@ -99997,4 +99997,4 @@
+ line 99996
+ line 99997
+ line 99998
+-line 99999
++line 0
+`).Test(t)
+}
+
+func TestExpectFileMode(t *testing.T) {
+	xycond.ExpectFileMode(testFS, "run.sh", 0o755).Test(t)
+	xycond.ExpectFileMode(testFS, "docs", fs.ModeDir|0o755).Test(t)
+
+	var c = xycond.ExpectFileMode(testFS, "run.sh", 0o600)
+	xycond.ExpectEqual(c.Message(),
+		`file "run.sh": expect mode -rw-------, but got -rwxr-xr-x`).Test(t)
+}
+
+func TestExpectFileChecksum(t *testing.T) {
+	const sum = "" +
+		"df1d036cbbf3df46e2045071e082245ece204c7f53ecf0a4e022bff9bb228f47"
+	xycond.ExpectFileChecksum(testFS, "cmd/main.go", sum).Test(t)
+	xycond.ExpectFileChecksum(testFS, "cmd/main.go", strings.ToUpper(sum)).
+		Test(t)
+
+	var c = xycond.ExpectFileChecksum(testFS, "cmd/main.go", "00")
+	xycond.ExpectEqual(c.Message(),
+		`file "cmd/main.go": expect sha256 00, but got `+sum).Test(t)
+}
+
+func TestExpectTree(t *testing.T) {
+	var dir = t.TempDir()
+	for name, f := range testFS {
+		var path = filepath.Join(dir, filepath.FromSlash(name))
+		var err error
+		if f.Mode.IsDir() {
+			err = os.MkdirAll(path, 0o755)
+		} else {
+			xycond.ExpectNil(os.MkdirAll(filepath.Dir(path), 0o755)).Test(t)
+			err = os.WriteFile(path, f.Data, f.Mode)
+		}
+		xycond.ExpectNil(err).Test(t)
+	}
+
+	xycond.ExpectTree(os.DirFS(dir), testFS).Test(t)
+	xycond.ExpectFileMode(os.DirFS(dir), "run.sh", 0o755).Test(t)
+
+	var spec = fstest.MapFS{
+		"cmd/main.go": {Data: []byte("package cmd\n")},
+		"run.sh":      {Data: []byte("#!/bin/sh\n")},
+		"README.md":   {Data: []byte("# Project\n")},
+	}
+	xycond.ExpectEqual(xycond.ExpectTree(os.DirFS(dir), spec).Message(),
+		`mismatched tree
+missing: README.md
+different: cmd/main.go
+--- want
++++ cmd/main.go
+@@ -1 +1 @@
+-package cmd
++package main
+extra: docs`).Test(t)
+}
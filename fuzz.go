@@ -0,0 +1,134 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// fuzzer instances may be *testing.F.
+type fuzzer interface {
+	Helper()
+	Fuzz(ff any)
+}
+
+// fuzzTest instances may be *testing.T.
+type fuzzTest interface {
+	failer
+	Error(args ...any)
+}
+
+var (
+	fuzzTestType  = reflect.TypeOf((*fuzzTest)(nil)).Elem()
+	conditionType = reflect.TypeOf(Condition{})
+)
+
+// Fuzz registers the fuzz target fn to the fuzz test, which is usually a
+// *testing.F. The fuzz target must be a function of *testing.T and fuzzed
+// arguments returning a Condition, e.g.
+// func(t *testing.T, s string, n int) Condition. The fuzz input fails if
+// the Condition is false, and the failure message with the pretty printed
+// input is reported by t.Error, which appears in the crash report.
+func Fuzz(f fuzzer, fn any) {
+	f.Helper()
+	var _, file, line, _ = runtime.Caller(1)
+
+	var fv = reflect.ValueOf(fn)
+	ExpectIs(fn, reflect.Func).must()
+	var ft = fv.Type()
+	NewCondition(!ft.IsVariadic() && ft.NumIn() > 0 &&
+		ft.In(0).Implements(fuzzTestType) && ft.NumOut() == 1 &&
+		ft.Out(0) == conditionType,
+		func() string {
+			return fmt.Sprintf("expect a fuzz target "+
+				"func(*testing.T, ...) xycond.Condition, but got %s", ft)
+		}).must()
+
+	var in = make([]reflect.Type, ft.NumIn())
+	for i := range in {
+		in[i] = ft.In(i)
+	}
+
+	var target = reflect.MakeFunc(reflect.FuncOf(in, nil, false),
+		func(args []reflect.Value) []reflect.Value {
+			var t = args[0].Interface().(fuzzTest)
+			var c = fv.Call(args)[0].Interface().(Condition)
+			if !c.result {
+				c.fuzz(t, file, line, args[1:])
+			}
+			return nil
+		})
+	f.Fuzz(target.Interface())
+}
+
+// fuzz reports the false Condition of the fuzz target registered at the
+// location with the input.
+func (c Condition) fuzz(
+	t fuzzTest, fn string, ln int, input []reflect.Value,
+) {
+	var msg = c.generateMessage()
+	annotate(c.notify(t, msg, fn, ln))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s:%d: %s\ninput:", filepath.Base(fn), ln, msg)
+	for i := range input {
+		fmt.Fprintf(&sb, "\n    #%d: %s", i+1, Sprint(input[i].Interface()))
+	}
+	t.Error(sb.String())
+}
+
+// ExpectRoundTrip returns a true Condition if decoding the encoded value
+// produces a value deeply equal to the original one.
+func ExpectRoundTrip[T any](
+	v T,
+	encode func(T) ([]byte, error),
+	decode func([]byte) (T, error),
+) Condition {
+	var got T
+	var data, err = encode(v)
+	if err == nil {
+		got, err = decode(data)
+	}
+	if err == nil && reflect.DeepEqual(v, got) {
+		return newCondition(true, opRoundTrip)
+	}
+	return newCondition(false, opRoundTrip, v, got, err)
+}
+
+// ExpectNoPanic returns a true Condition if calling the function doesn't
+// panic. The message of a false Condition contains the stack trace of the
+// panic.
+func ExpectNoPanic(f func()) (c Condition) {
+	c = newCondition(true, opNoPanic)
+	defer func() {
+		if r := recover(); r != nil {
+			c = newCondition(false, opNoPanic, r, string(debug.Stack()))
+		}
+	}()
+
+	f()
+	return
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/xybor-x/xycond"
+)
+
+func FuzzFuzz(f *testing.F) {
+	f.Add("foo", 1)
+	f.Add("", 0)
+
+	expectPanicMessage(f, "expect a fuzz target func(*testing.T, ...) "+
+		"xycond.Condition, but got func(string) xycond.Condition", func() {
+		xycond.Fuzz(f, func(string) xycond.Condition {
+			return xycond.ExpectTrue(true)
+		})
+	})
+
+	xycond.Fuzz(f, func(t *testing.T, s string, n int) xycond.Condition {
+		return xycond.ExpectEqual(utf8.RuneCountInString(strings.Repeat(s, 2)),
+			2*utf8.RuneCountInString(s))
+	})
+}
+
+// fakeFuzzer records the registered fuzz target.
+type fakeFuzzer struct {
+	target any
+}
+
+func (f *fakeFuzzer) Helper()     {}
+func (f *fakeFuzzer) Fuzz(ff any) { f.target = ff }
+
+// fakeFuzzTest records the failures of a fuzz input.
+type fakeFuzzTest struct {
+	failed bool
+	errors []string
+}
+
+func (t *fakeFuzzTest) Fail() { t.failed = true }
+
+func (t *fakeFuzzTest) Error(args ...any) {
+	t.failed = true
+	t.errors = append(t.errors, fmt.Sprint(args...))
+}
+
+func TestFuzzFailure(t *testing.T) {
+	var f = &fakeFuzzer{}
+	var _, _, line, _ = runtime.Caller(0)
+	xycond.Fuzz(f, func(_ *fakeFuzzTest, s string, n int) xycond.Condition {
+		return xycond.ExpectEqual(len(s), n)
+	})
+
+	var ft = &fakeFuzzTest{}
+	reflect.ValueOf(f.target).Call([]reflect.Value{
+		reflect.ValueOf(ft), reflect.ValueOf("foo"), reflect.ValueOf(4),
+	})
+	xycond.ExpectTrue(ft.failed).Test(t)
+	xycond.ExpectEqual(len(ft.errors), 1).Test(t)
+	xycond.ExpectEqual(ft.errors[0], fmt.Sprintf("fuzz_test.go:%d: 3 != 4\n"+
+		"input:\n    #1: \"foo\"\n    #2: 4", line+1)).Test(t)
+
+	ft = &fakeFuzzTest{}
+	reflect.ValueOf(f.target).Call([]reflect.Value{
+		reflect.ValueOf(ft), reflect.ValueOf("foo"), reflect.ValueOf(3),
+	})
+	xycond.ExpectFalse(ft.failed).Test(t)
+}
+
+func FuzzExpectRoundTrip(f *testing.F) {
+	f.Add("foo")
+	f.Add("\x00<>&")
+
+	xycond.Fuzz(f, func(t *testing.T, s string) xycond.Condition {
+		if !utf8.ValidString(s) {
+			t.Skip("invalid UTF-8 is replaced by encoding/json")
+		}
+		return xycond.ExpectRoundTrip(s,
+			func(s string) ([]byte, error) { return json.Marshal(s) },
+			func(b []byte) (string, error) {
+				var s string
+				return s, json.Unmarshal(b, &s)
+			})
+	})
+}
+
+func TestExpectRoundTrip(t *testing.T) {
+	var encode = func(s string) ([]byte, error) { return []byte(s), nil }
+	var decode = func(b []byte) (string, error) {
+		return strings.ToLower(string(b)), nil
+	}
+
+	xycond.ExpectRoundTrip("foo", encode, decode).Test(t)
+
+	var c = xycond.ExpectRoundTrip("Foo", encode, decode)
+	xycond.ExpectFalse(c.OK()).Test(t)
+	xycond.ExpectEqual(c.Op(), "round_trip").Test(t)
+	xycond.ExpectEqual(c.Message(),
+		`expect "Foo" after round trip, but got "foo"`).Test(t)
+
+	c = xycond.ExpectRoundTrip("foo", encode,
+		func([]byte) (string, error) { return "", errors.New("bad input") })
+	xycond.ExpectEqual(c.Message(),
+		`round trip of "foo" failed: bad input`).Test(t)
+}
+
+func TestExpectNoPanic(t *testing.T) {
+	xycond.ExpectNoPanic(func() {}).Test(t)
+
+	var c = xycond.ExpectNoPanic(func() { panic("boom") })
+	xycond.ExpectFalse(c.OK()).Test(t)
+	xycond.ExpectEqual(c.Op(), "no_panic").Test(t)
+	xycond.ExpectEqual(len(c.Params()), 1).Test(t)
+	xycond.ExpectTrue(strings.HasPrefix(c.Message(),
+		"expect no panic, but got \"boom\"\ngoroutine ")).Test(t)
+}
@@ -1,6 +1,6 @@
 module github.com/xybor-x/xycond
 
-go 1.18
+go 1.21
 
 require github.com/xybor-x/xyerror v1.0.0
 
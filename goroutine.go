@@ -0,0 +1,160 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExpectCompletesWithin returns a true Condition if the function returns
+// within the duration. The function is run in a new goroutine, which is left
+// running if it doesn't return in time. The failure message contains the
+// goroutine dump of the process, which is filtered to goroutines and frames
+// outside the runtime, testing, and this package.
+func ExpectCompletesWithin(d time.Duration, f func()) Condition {
+	var done = make(chan any, 1)
+	var started = make(chan uint64, 1)
+	go func() {
+		started <- goroutineID()
+		defer func() {
+			done <- recover()
+		}()
+		f()
+	}()
+
+	var id = <-started
+	var timer = time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		if r == nil {
+			return newCondition(true, opCompletesWithin, d)
+		}
+		return newCondition(false, opCompletesWithin, d,
+			"it panicked with "+Sprint(r))
+	case <-timer.C:
+		return newCondition(false, opCompletesWithin, d,
+			"it's still running\n"+goroutineDump(id, goroutineID()))
+	}
+}
+
+// ExpectBlocks returns a true Condition if the function doesn't return within
+// the duration. The function is run in a new goroutine, which is left running
+// if it blocks.
+func ExpectBlocks(d time.Duration, f func()) Condition {
+	var done = make(chan time.Duration, 1)
+	var start = time.Now()
+	go func() {
+		defer func() {
+			recover()
+			done <- time.Since(start)
+		}()
+		f()
+	}()
+
+	var timer = time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case elapsed := <-done:
+		return newCondition(false, opBlocks, d, elapsed)
+	case <-timer.C:
+		return newCondition(true, opBlocks, d)
+	}
+}
+
+// goroutineDump returns stacks of all goroutines except the skipped one. The
+// goroutine first is printed first, other goroutines are only printed if they
+// have relevant frames.
+func goroutineDump(first, skip uint64) string {
+	var buf = make([]byte, 1<<16)
+	for {
+		var n = runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	var head, tail []string
+	for _, block := range strings.Split(string(buf), "\n\n") {
+		var id, stack, relevant = filterStack(block)
+		switch {
+		case id == skip:
+		case id == first:
+			head = append(head, stack)
+		case relevant:
+			tail = append(tail, stack)
+		}
+	}
+	return strings.Join(append(head, tail...), "\n\n")
+}
+
+// filterStack removes irrelevant frames from the stack of a goroutine. It
+// returns the goroutine ID and whether the stack has any relevant frames.
+func filterStack(block string) (uint64, string, bool) {
+	var lines = strings.Split(strings.TrimSpace(block), "\n")
+	var header = strings.TrimPrefix(lines[0], "goroutine ")
+	var idStr, _, _ = strings.Cut(header, " ")
+	var id, _ = strconv.ParseUint(idStr, 10, 64)
+
+	var sb strings.Builder
+	sb.WriteString(lines[0])
+	var relevant = false
+	for i := 1; i < len(lines); i += 2 {
+		var fn = lines[i]
+		var created = strings.HasPrefix(fn, "created by ")
+		var name = strings.TrimPrefix(fn, "created by ")
+		if j := strings.LastIndexByte(name, '('); j > 0 && !created {
+			name = name[:j]
+		}
+		var loc string
+		if i+1 < len(lines) {
+			loc = lines[i+1]
+		}
+		// The generated main function of test binaries is irrelevant too.
+		if !created && (isIrrelevantFunc(name) ||
+			strings.Contains(loc, "_testmain.go:")) {
+			continue
+		}
+
+		relevant = relevant || !created
+		sb.WriteString("\n")
+		sb.WriteString(fn)
+		if loc != "" {
+			sb.WriteString("\n")
+			sb.WriteString(loc)
+		}
+	}
+	return id, sb.String(), relevant
+}
+
+// isIrrelevantFunc returns true if the function is defined in the runtime,
+// testing, or this package.
+func isIrrelevantFunc(name string) bool {
+	return strings.HasPrefix(name, "runtime.") ||
+		strings.HasPrefix(name, "testing.") || isInternalFunc(name)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xybor-x/xycond"
+)
+
+func lockTwice(mu *sync.Mutex) {
+	mu.Lock()
+	mu.Lock()
+}
+
+func TestExpectCompletesWithin(t *testing.T) {
+	xycond.ExpectCompletesWithin(time.Second, func() {}).Test(t)
+
+	var mu sync.Mutex
+	var c = xycond.ExpectCompletesWithin(10*time.Millisecond, func() {
+		lockTwice(&mu)
+	})
+	defer mu.Unlock()
+
+	var msg = c.Message()
+	xycond.ExpectFalse(c.OK()).Test(t)
+	xycond.ExpectTrue(strings.HasPrefix(msg, "expect the function to "+
+		"complete within 10ms, but it's still running\ngoroutine ")).Test(t)
+	xycond.ExpectIn("xycond_test.lockTwice", msg).Test(t)
+	xycond.ExpectIn("created by github.com/xybor-x/xycond."+
+		"ExpectCompletesWithin", msg).Test(t)
+	xycond.ExpectNotIn("runtime.gopark", msg).Test(t)
+	xycond.ExpectNotIn("testing.tRunner", msg).Test(t)
+
+	c = xycond.ExpectCompletesWithin(time.Second, func() { panic("boom") })
+	xycond.ExpectEqual(c.Message(), "expect the function to complete "+
+		"within 1s, but it panicked with \"boom\"").Test(t)
+}
+
+func TestExpectBlocks(t *testing.T) {
+	var ch = make(chan int)
+	xycond.ExpectBlocks(10*time.Millisecond, func() { <-ch }).Test(t)
+	close(ch)
+
+	var c = xycond.ExpectBlocks(time.Second, func() {})
+	xycond.ExpectFalse(c.OK()).Test(t)
+	xycond.ExpectTrue(strings.HasPrefix(c.Message(),
+		"expect the function to block for 1s, but it returned after ")).
+		Test(t)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// FailureEvent describes a false Condition which is asserted or tested.
+type FailureEvent struct {
+	// Op is the stable name of the Condition operator, e.g. "equal".
+	Op string
+
+	// Message is the rendered failure message.
+	Message string
+
+	// Params are the values checked by the Condition.
+	Params []any
+
+	// File and Line are the location where the Condition is asserted or
+	// tested.
+	File string
+	Line int
+
+	// Goroutine is the ID of the goroutine where the failure happens.
+	Goroutine uint64
+
+	// Test is the name of the test if the Condition is tested with a
+	// *testing.T or *testing.B.
+	Test string
+}
+
+// hookList contains failure hooks which can be removed.
+type hookList struct {
+	lock  sync.RWMutex
+	next  int
+	hooks []hookEntry
+}
+
+type hookEntry struct {
+	id int
+	f  func(FailureEvent)
+}
+
+// globalHooks are called on every failure.
+var globalHooks hookList
+
+// OnFailure registers a hook which is called with every false Condition before
+// Assert handles it or Test fails. It returns a function to remove the hook.
+func OnFailure(f func(FailureEvent)) (remove func()) {
+	return globalHooks.add(f)
+}
+
+// OnFailure registers a hook which is called with every false Condition
+// handled by the Asserter, before the hooks registered by the global
+// OnFailure. It returns a function to remove the hook.
+func (a *Asserter) OnFailure(f func(FailureEvent)) (remove func()) {
+	return a.hooks.add(f)
+}
+
+func (l *hookList) add(f func(FailureEvent)) func() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var id = l.next
+	l.next++
+	l.hooks = append(l.hooks, hookEntry{id: id, f: f})
+
+	return func() {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		for i := range l.hooks {
+			if l.hooks[i].id == id {
+				l.hooks = append(l.hooks[:i:i], l.hooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (l *hookList) call(e FailureEvent) {
+	l.lock.RLock()
+	var hooks = l.hooks
+	l.lock.RUnlock()
+
+	for i := range hooks {
+		hooks[i].f(e)
+	}
+}
+
+// newEvent returns the FailureEvent of the false Condition.
+func (c Condition) newEvent(msg, file string, line int) FailureEvent {
+	return FailureEvent{
+		Op:        c.op.String(),
+		Message:   msg,
+		Params:    c.values(),
+		File:      file,
+		Line:      line,
+		Goroutine: goroutineID(),
+	}
+}
+
+// values returns a copy of the parameters of the Condition which are checked
+// values.
+func (c Condition) values() []any {
+	var n = c.n
+	switch c.op {
+	case opTrue, opFalse, opCustom:
+		n = 0
+	case opMatch, opNotMatch, opNoPanic:
+		n = min(n, 1)
+	}
+	if n == 0 {
+		return nil
+	}
+	return append([]any(nil), c.params[:n]...)
+}
+
+// goroutineID returns the ID of the current goroutine, which is parsed from
+// the header of its stack trace, e.g. "goroutine 18 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	var b = buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	var id, _ = strconv.ParseUint(string(b), 10, 64)
+	return id
+}
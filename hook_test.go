@@ -0,0 +1,74 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+	"github.com/xybor-x/xyerror"
+)
+
+func TestOnFailure(t *testing.T) {
+	var events []xycond.FailureEvent
+	var remove = xycond.OnFailure(func(e xycond.FailureEvent) {
+		events = append(events, e)
+	})
+
+	xycond.ExpectPanic(xyerror.AssertionError, func() {
+		xycond.AssertEqual(1, 2)
+	}).Test(t)
+	xycond.ExpectIn(3, []int{1, 2}).Test(mocktest{})
+	xycond.AssertTrue(true)
+
+	remove()
+	xycond.ExpectTrue(false).Test(mocktest{})
+
+	xycond.ExpectEqual(len(events), 2).Test(t)
+	xycond.ExpectEqual(events[0].Op, "equal").Test(t)
+	xycond.ExpectEqual(events[0].Message, "1 != 2").Test(t)
+	xycond.ExpectEqual(len(events[0].Params), 2).Test(t)
+	xycond.ExpectTrue(strings.HasSuffix(events[0].File, "hook_test.go")).Test(t)
+	xycond.ExpectNotZero(events[0].Line).Test(t)
+	xycond.ExpectNotZero(events[0].Goroutine).Test(t)
+
+	xycond.ExpectEqual(events[1].Op, "in").Test(t)
+	xycond.ExpectEqual(events[1].Message, "3 NOT IN []int{1, 2}").Test(t)
+	xycond.ExpectTrue(strings.HasSuffix(events[1].File, "hook_test.go")).Test(t)
+}
+
+func TestAsserterOnFailure(t *testing.T) {
+	var order []string
+	var a = xycond.NewAsserter(xycond.PolicyIgnore)
+	defer a.OnFailure(func(e xycond.FailureEvent) {
+		order = append(order, "asserter "+e.Message)
+	})()
+	defer xycond.OnFailure(func(e xycond.FailureEvent) {
+		order = append(order, "global "+e.Message)
+	})()
+
+	a.Assert(xycond.ExpectFalse(true), "foo")
+	xycond.NewAsserter(xycond.PolicyIgnore).Assert(xycond.ExpectNil(1), "bar")
+
+	xycond.ExpectEqual(strings.Join(order, ","),
+		"asserter foo,global foo,global bar").Test(t)
+}
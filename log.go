@@ -0,0 +1,96 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// LogValue implements slog.LogValuer, a Condition is logged as a group of op,
+// result, params, and, if it is false, message and caller when they are known.
+func (c Condition) LogValue() slog.Value {
+	var file, line = c.caller()
+	var attrs = c.logAttrs(file, line)
+	attrs = append([]slog.Attr{slog.Bool("result", c.result)}, attrs...)
+	if !c.result {
+		attrs = append(attrs, slog.String("message", c.generateMessage()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Log logs the generated message with structured attributes if it is a false
+// Condition. The default logger is used if the logger is nil.
+func (c Condition) Log(logger *slog.Logger, level slog.Level) Condition {
+	if c.result {
+		return c
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	var ctx = context.Background()
+	if !logger.Enabled(ctx, level) {
+		return c
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	var record = slog.NewRecord(time.Now(), level, c.generateMessage(), pcs[0])
+	var caller = externalCaller()
+	record.AddAttrs(c.logAttrs(caller.File, caller.Line)...)
+	_ = logger.Handler().Handle(ctx, record)
+
+	return c
+}
+
+// logAttrs returns the op and params of the Condition, and the caller if the
+// file is not empty.
+func (c Condition) logAttrs(file string, line int) []slog.Attr {
+	var attrs = []slog.Attr{slog.String("op", c.op.String())}
+
+	if values := c.values(); len(values) > 0 {
+		var params = make([]string, len(values))
+		for i := range values {
+			params[i] = Sprint(values[i])
+		}
+		attrs = append(attrs, slog.Any("params", params))
+	}
+
+	if file != "" {
+		attrs = append(attrs,
+			slog.String("caller", fmt.Sprintf("%s:%d", file, line)))
+	}
+
+	return attrs
+}
+
+// caller returns the call site captured by the Condition, if any.
+func (c Condition) caller() (file string, line int) {
+	if (c.op == opTrue || c.op == opFalse) && c.n > 0 {
+		var site = c.params[0].(callSite)
+		return site.file, site.line
+	}
+	return "", 0
+}
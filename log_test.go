@@ -0,0 +1,78 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+)
+
+func TestConditionLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	var logger = slog.New(slog.NewJSONHandler(&buf, nil))
+
+	logger.Info("check", "cond", xycond.ExpectEqual(1, 2))
+
+	var record struct {
+		Cond struct {
+			Result  bool
+			Op      string
+			Params  []string
+			Message string
+		}
+	}
+	xycond.ExpectNil(json.Unmarshal(buf.Bytes(), &record)).Test(t)
+	xycond.ExpectFalse(record.Cond.Result).Test(t)
+	xycond.ExpectEqual(record.Cond.Op, "equal").Test(t)
+	xycond.ExpectEqual(strings.Join(record.Cond.Params, ","), "1,2").Test(t)
+	xycond.ExpectEqual(record.Cond.Message, "1 != 2").Test(t)
+
+	buf.Reset()
+	logger.Info("check", "cond", xycond.ExpectTrue(true))
+	xycond.ExpectIn(`"cond":{"result":true,"op":"true"}`, buf.String()).Test(t)
+
+	buf.Reset()
+	logger.Info("check", "cond", xycond.ExpectTrue(false))
+	xycond.ExpectIn(`"caller":`, buf.String()).Test(t)
+	xycond.ExpectIn("log_test.go", buf.String()).Test(t)
+}
+
+func TestConditionLog(t *testing.T) {
+	var buf bytes.Buffer
+	var logger = slog.New(slog.NewTextHandler(&buf,
+		&slog.HandlerOptions{AddSource: true}))
+
+	xycond.ExpectEqual(1, 1).Log(logger, slog.LevelWarn)
+	xycond.ExpectEqual(1, 2).Log(logger, slog.LevelDebug)
+	xycond.ExpectEmpty(buf.String()).Test(t)
+
+	xycond.ExpectEqual(1, 2).Log(logger, slog.LevelWarn).True(t.Fail)
+	xycond.ExpectIn(`level=WARN`, buf.String()).Test(t)
+	xycond.ExpectIn(`source=`, buf.String()).Test(t)
+	xycond.ExpectIn(`msg="1 != 2" op=equal params="[1 2]" caller=`,
+		buf.String()).Test(t)
+	xycond.ExpectIn("log_test.go", buf.String()).Test(t)
+}
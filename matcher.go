@@ -0,0 +1,102 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import "fmt"
+
+// Matcher is a custom check which can be used to create Conditions rendering
+// like the built-in ones.
+type Matcher interface {
+	// Match returns true if the actual value satisfies the check.
+	Match(actual any) bool
+
+	// FailureMessage returns the message used when Match returns false.
+	FailureMessage(actual any) string
+
+	// NegatedFailureMessage returns the message used when Match returns true
+	// while a negated Condition is expected.
+	NegatedFailureMessage(actual any) string
+}
+
+// NewMatcher returns a Matcher with messages generated from the description,
+// e.g. "expect a valid SKU, but got foo".
+func NewMatcher(description string, match func(actual any) bool) Matcher {
+	return funcMatcher{description: description, match: match}
+}
+
+// Not returns a Matcher which succeeds if the passed Matcher fails.
+func Not(m Matcher) Matcher {
+	if n, ok := m.(notMatcher); ok {
+		return n.m
+	}
+	return notMatcher{m: m}
+}
+
+// Expect returns a true Condition if the actual value matches the Matcher.
+func Expect(actual any, m Matcher) Condition {
+	return newCondition(m.Match(actual), opMatch, actual, m)
+}
+
+// ExpectNot returns a true Condition if the actual value doesn't match the
+// Matcher.
+func ExpectNot(actual any, m Matcher) Condition {
+	return newCondition(!m.Match(actual), opNotMatch, actual, m)
+}
+
+// NewCondition returns a Condition of the result, msg is only called to
+// generate the message when the Condition is false.
+func NewCondition(ok bool, msg func() string) Condition {
+	return newCondition(ok, opCustom, msg)
+}
+
+type funcMatcher struct {
+	description string
+	match       func(any) bool
+}
+
+func (m funcMatcher) Match(actual any) bool {
+	return m.match(actual)
+}
+
+func (m funcMatcher) FailureMessage(actual any) string {
+	return fmt.Sprintf("expect %s, but got %s", m.description, Sprint(actual))
+}
+
+func (m funcMatcher) NegatedFailureMessage(actual any) string {
+	return fmt.Sprintf("expect not %s, but got %s", m.description,
+		Sprint(actual))
+}
+
+type notMatcher struct {
+	m Matcher
+}
+
+func (n notMatcher) Match(actual any) bool {
+	return !n.m.Match(actual)
+}
+
+func (n notMatcher) FailureMessage(actual any) string {
+	return n.m.NegatedFailureMessage(actual)
+}
+
+func (n notMatcher) NegatedFailureMessage(actual any) string {
+	return n.m.FailureMessage(actual)
+}
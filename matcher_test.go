@@ -0,0 +1,96 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+	"github.com/xybor-x/xyerror"
+)
+
+type skuMatcher struct{}
+
+func (skuMatcher) Match(actual any) bool {
+	return strings.HasPrefix(actual.(string), "SKU-")
+}
+
+func (skuMatcher) FailureMessage(actual any) string {
+	return "invalid SKU " + actual.(string)
+}
+
+func (skuMatcher) NegatedFailureMessage(actual any) string {
+	return "valid SKU " + actual.(string)
+}
+
+func expectPanicMessage(t testing.TB, msg string, f func()) {
+	defer func() {
+		var r = recover()
+		xycond.ExpectError(r.(error), xyerror.AssertionError).Test(t)
+		xycond.ExpectEqual(r.(error).Error(), "AssertionError: "+msg).Test(t)
+	}()
+	f()
+}
+
+func TestExpectMatcher(t *testing.T) {
+	xycond.Expect("SKU-1", skuMatcher{}).Test(t)
+	xycond.ExpectNot("1", skuMatcher{}).Test(t)
+	xycond.Expect("1", xycond.Not(skuMatcher{})).Test(t)
+	xycond.Expect("SKU-1", xycond.Not(xycond.Not(skuMatcher{}))).Test(t)
+
+	expectPanicMessage(t, "invalid SKU 1", func() {
+		xycond.Expect("1", skuMatcher{}).Assert("")
+	})
+	expectPanicMessage(t, "valid SKU SKU-1", func() {
+		xycond.ExpectNot("SKU-1", skuMatcher{}).Assert("")
+	})
+	expectPanicMessage(t, "valid SKU SKU-1", func() {
+		xycond.Expect("SKU-1", xycond.Not(skuMatcher{})).Assert("")
+	})
+}
+
+func TestNewMatcher(t *testing.T) {
+	var positive = xycond.NewMatcher("a positive number", func(a any) bool {
+		return a.(int) > 0
+	})
+
+	expectPanicMessage(t, "expect a positive number, but got -1", func() {
+		xycond.Expect(-1, positive).Assert("")
+	})
+	expectPanicMessage(t, "expect not a positive number, but got 1", func() {
+		xycond.ExpectNot(1, positive).Assert("")
+	})
+}
+
+func TestNewCondition(t *testing.T) {
+	var called = false
+	xycond.NewCondition(true, func() string {
+		called = true
+		return ""
+	}).Test(t)
+	xycond.ExpectFalse(called).Test(t)
+
+	xycond.NewCondition(false, nil).True(t.Fail)
+	expectPanicMessage(t, "foo", func() {
+		xycond.NewCondition(false, func() string { return "foo" }).Assert("")
+	})
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"runtime"
+	"sort"
+	"time"
+)
+
+// allocsRuns is the number of runs to measure allocations.
+const allocsRuns = 100
+
+// ExpectAllocsAtMost returns a true Condition if the function allocates at most
+// n times per run on average, which is measured as testing.AllocsPerRun does.
+func ExpectAllocsAtMost(n int, f func()) Condition {
+	var got = allocsPerRun(allocsRuns, f)
+	if got <= float64(n) {
+		return newCondition(true, opAllocsAtMost)
+	}
+	return newCondition(false, opAllocsAtMost, n, got)
+}
+
+// ExpectNoAllocs returns a true Condition if the function doesn't allocate.
+func ExpectNoAllocs(f func()) Condition {
+	var got = allocsPerRun(allocsRuns, f)
+	if got == 0 {
+		return newCondition(true, opNoAllocs)
+	}
+	return newCondition(false, opNoAllocs, got)
+}
+
+// allocsPerRun returns the average number of allocations of calling the
+// function, which is run once more before measuring to warm up. It is the same
+// as testing.AllocsPerRun, which would link the testing package to binaries
+// asserting conditions.
+func allocsPerRun(runs int, f func()) float64 {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	f()
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	var mallocs = stats.Mallocs
+
+	for i := 0; i < runs; i++ {
+		f()
+	}
+
+	runtime.ReadMemStats(&stats)
+	// The average is an integer, as the one of testing.AllocsPerRun.
+	return float64((stats.Mallocs - mallocs) / uint64(runs))
+}
+
+// ExpectFasterThan returns a true Condition if the median time of running the
+// function is less than the duration. The function is run once more before
+// measuring to warm up caches.
+func ExpectFasterThan(d time.Duration, f func(), runs int) Condition {
+	ExpectGreaterThan(runs, 0).must()
+
+	f()
+
+	var times = make([]time.Duration, runs)
+	for i := range times {
+		var start = time.Now()
+		f()
+		times[i] = time.Since(start)
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	var median = times[runs/2]
+	if runs%2 == 0 {
+		median = (times[runs/2-1] + times[runs/2]) / 2
+	}
+
+	if median < d {
+		return newCondition(true, opFasterThan)
+	}
+	return newCondition(false, opFasterThan, d, median, runs)
+}
+
+// ExpectHeapGrowthAtMost returns a true Condition if the live heap grows by at
+// most the number of bytes after calling the function. The heap is measured
+// by runtime.MemStats after a garbage collection, so only memory retained by
+// the function is counted.
+func ExpectHeapGrowthAtMost(bytes uint64, f func()) Condition {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	f()
+
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+
+	var growth uint64
+	if after.HeapAlloc > before.HeapAlloc {
+		growth = after.HeapAlloc - before.HeapAlloc
+	}
+
+	if growth <= bytes {
+		return newCondition(true, opHeapGrowthAtMost)
+	}
+	return newCondition(false, opHeapGrowthAtMost, bytes, growth)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xybor-x/xycond"
+)
+
+var sink []byte
+
+func TestExpectAllocs(t *testing.T) {
+	var noop = func() {}
+	var alloc = func() { sink = make([]byte, 64) }
+
+	xycond.ExpectNoAllocs(noop).Test(t)
+	xycond.ExpectAllocsAtMost(0, noop).Test(t)
+	xycond.ExpectAllocsAtMost(1, alloc).Test(t)
+
+	var c = xycond.ExpectAllocsAtMost(0, alloc)
+	xycond.ExpectFalse(c.OK()).Test(t)
+	xycond.ExpectEqual(c.Message(),
+		"expect at most 0 allocations per run, but got 1").Test(t)
+
+	c = xycond.ExpectNoAllocs(alloc)
+	xycond.ExpectFalse(c.OK()).Test(t)
+	xycond.ExpectEqual(c.Message(),
+		"expect no allocations, but got 1 per run").Test(t)
+}
+
+func TestExpectFasterThan(t *testing.T) {
+	xycond.ExpectFasterThan(time.Hour, func() {}, 5).Test(t)
+
+	var c = xycond.ExpectFasterThan(time.Microsecond, func() {
+		time.Sleep(time.Millisecond)
+	}, 4)
+	xycond.ExpectFalse(c.OK()).Test(t)
+	xycond.ExpectTrue(strings.HasPrefix(c.Message(),
+		"expect a median time of 4 runs less than 1µs, but got ")).Test(t)
+
+	expectPanicMessage(t, "0 is not greater than 0", func() {
+		xycond.ExpectFasterThan(time.Hour, func() {}, 0)
+	})
+}
+
+func TestExpectHeapGrowthAtMost(t *testing.T) {
+	xycond.ExpectHeapGrowthAtMost(1<<20, func() {}).Test(t)
+
+	var c = xycond.ExpectHeapGrowthAtMost(1<<10, func() {
+		sink = make([]byte, 1<<20)
+	})
+	sink = nil
+	xycond.ExpectFalse(c.OK()).Test(t)
+	xycond.ExpectTrue(strings.HasPrefix(c.Message(),
+		"expect a heap growth of at most 1024 bytes, but got ")).Test(t)
+}
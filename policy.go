@@ -0,0 +1,208 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Policy decides what Assert does with a false Condition.
+type Policy int
+
+const (
+	// PolicyPanic panics with an assertion error, it is the default Policy.
+	PolicyPanic Policy = iota
+
+	// PolicyLog logs the failure message at the error level.
+	PolicyLog
+
+	// PolicyCount only increases the failure counter.
+	PolicyCount
+
+	// PolicyIgnore does nothing.
+	PolicyIgnore
+)
+
+var policyNames = []string{"panic", "log", "count", "ignore"}
+
+// String returns the name of the Policy.
+func (p Policy) String() string {
+	if p < 0 || int(p) >= len(policyNames) {
+		return fmt.Sprintf("Policy(%d)", int(p))
+	}
+	return policyNames[p]
+}
+
+// ParsePolicy returns the Policy of the name, which is one of "panic", "log",
+// "count", or "ignore".
+func ParsePolicy(name string) (Policy, error) {
+	for i := range policyNames {
+		if strings.EqualFold(name, policyNames[i]) {
+			return Policy(i), nil
+		}
+	}
+	return PolicyPanic, fmt.Errorf("xycond: unknown policy %q", name)
+}
+
+// Asserter handles false Conditions by its Policy. Failures in an Asserter are
+// counted even if the Policy is not PolicyCount.
+type Asserter struct {
+	lock     sync.RWMutex
+	policy   Policy
+	handler  slog.Handler
+	failures atomic.Uint64
+	hooks    hookList
+}
+
+// defaultAsserter is used by Condition.Assert, its Policy is initialized by
+// the environment variable XYCOND_POLICY.
+var defaultAsserter = &Asserter{policy: envPolicy()}
+
+func envPolicy() Policy {
+	var p, err = ParsePolicy(os.Getenv("XYCOND_POLICY"))
+	if err != nil {
+		return PolicyPanic
+	}
+	return p
+}
+
+// NewAsserter returns an Asserter with the Policy.
+func NewAsserter(p Policy) *Asserter {
+	return &Asserter{policy: p}
+}
+
+// SetPolicy changes the Policy of the Asserter.
+func (a *Asserter) SetPolicy(p Policy) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.policy = p
+}
+
+// Policy returns the Policy of the Asserter.
+func (a *Asserter) Policy() Policy {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.policy
+}
+
+// SetLogHandler changes the handler used by PolicyLog. The handler of
+// slog.Default() is used if it is nil.
+func (a *Asserter) SetLogHandler(h slog.Handler) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.handler = h
+}
+
+// Failures returns the number of false Conditions handled by the Asserter.
+func (a *Asserter) Failures() uint64 {
+	return a.failures.Load()
+}
+
+// Assert handles the Condition by the Policy if it is false. The generated
+// message is used if msg is empty.
+func (a *Asserter) Assert(c Condition, msg string) {
+	if !c.result {
+		a.fail(context.Background(), c, msg)
+	}
+}
+
+// Assertf is the same as Assert, but with a format message.
+func (a *Asserter) Assertf(c Condition, msg string, args ...any) {
+	if !c.result {
+		a.fail(context.Background(), c, fmt.Sprintf(msg, args...))
+	}
+}
+
+// AssertContext is the same as Assert, but the Policy set by WithPolicy in
+// the context takes precedence over the Policy of the Asserter.
+func (a *Asserter) AssertContext(ctx context.Context, c Condition, msg string) {
+	if !c.result {
+		a.fail(ctx, c, msg)
+	}
+}
+
+func (a *Asserter) fail(ctx context.Context, c Condition, msg string) {
+	a.failures.Add(1)
+
+	a.lock.RLock()
+	var policy, handler = a.policy, a.handler
+	a.lock.RUnlock()
+
+	if p, ok := ctx.Value(policyKey{}).(Policy); ok {
+		policy = p
+	}
+
+	if msg == "" {
+		msg = c.generateMessage()
+	}
+
+	var caller = externalCaller()
+	var event = c.newEvent(msg, caller.File, caller.Line)
+	a.hooks.call(event)
+	globalHooks.call(event)
+
+	switch policy {
+	case PolicyPanic:
+		annotatePanic(event)
+		Panic(msg)
+	case PolicyLog:
+		if handler == nil {
+			handler = slog.Default().Handler()
+		}
+		if !handler.Enabled(ctx, slog.LevelError) {
+			return
+		}
+		var record = slog.NewRecord(time.Now(), slog.LevelError, msg, 0)
+		record.AddAttrs(c.logAttrs(caller.File, caller.Line)...)
+		_ = handler.Handle(ctx, record)
+	}
+}
+
+// SetPolicy changes the Policy used by Condition.Assert.
+func SetPolicy(p Policy) {
+	defaultAsserter.SetPolicy(p)
+}
+
+// SetLogHandler changes the handler used by Condition.Assert with PolicyLog.
+func SetLogHandler(h slog.Handler) {
+	defaultAsserter.SetLogHandler(h)
+}
+
+// Failures returns the number of false Conditions handled by
+// Condition.Assert.
+func Failures() uint64 {
+	return defaultAsserter.Failures()
+}
+
+type policyKey struct{}
+
+// WithPolicy returns a copy of the context in which the Policy is used by
+// AssertContext.
+func WithPolicy(ctx context.Context, p Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, p)
+}
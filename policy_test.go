@@ -0,0 +1,107 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+	"github.com/xybor-x/xyerror"
+)
+
+func TestParsePolicy(t *testing.T) {
+	for _, p := range []xycond.Policy{xycond.PolicyPanic, xycond.PolicyLog,
+		xycond.PolicyCount, xycond.PolicyIgnore} {
+		var parsed, err = xycond.ParsePolicy(p.String())
+		xycond.ExpectNil(err).Test(t)
+		xycond.ExpectEqual(parsed, p).Test(t)
+	}
+
+	var _, err = xycond.ParsePolicy("foo")
+	xycond.ExpectNotNil(err).Test(t)
+	xycond.ExpectEqual(xycond.Policy(10).String(), "Policy(10)").Test(t)
+}
+
+func TestAsserterPolicy(t *testing.T) {
+	var a = xycond.NewAsserter(xycond.PolicyPanic)
+	xycond.ExpectPanic(xyerror.AssertionError, func() {
+		a.Assert(xycond.ExpectEqual(1, 2), "")
+	}).Test(t)
+
+	var buf bytes.Buffer
+	a.SetPolicy(xycond.PolicyLog)
+	a.SetLogHandler(slog.NewTextHandler(&buf, nil))
+	a.Assert(xycond.ExpectEqual(1, 2), "")
+	a.Assertf(xycond.ExpectEqual(1, 1), "foo %d", 1)
+	xycond.ExpectIn(`level=ERROR msg="1 != 2" op=equal params="[1 2]" caller=`,
+		buf.String()).Test(t)
+	xycond.ExpectIn("policy_test.go", buf.String()).Test(t)
+
+	a.SetPolicy(xycond.PolicyCount)
+	a.Assertf(xycond.ExpectEqual(1, 2), "foo %d", 1)
+	a.SetPolicy(xycond.PolicyIgnore)
+	a.Assert(xycond.ExpectEqual(1, 2), "")
+
+	xycond.ExpectEqual(a.Policy(), xycond.PolicyIgnore).Test(t)
+	xycond.ExpectEqual(a.Failures(), uint64(4)).Test(t)
+}
+
+func TestPolicyContext(t *testing.T) {
+	var a = xycond.NewAsserter(xycond.PolicyPanic)
+	var ctx = xycond.WithPolicy(context.Background(), xycond.PolicyIgnore)
+	a.AssertContext(ctx, xycond.ExpectEqual(1, 2), "")
+
+	xycond.ExpectEqual(1, 2).AssertContext(ctx, "")
+	xycond.ExpectPanic(xyerror.AssertionError, func() {
+		xycond.ExpectEqual(1, 2).AssertContext(context.Background(), "")
+	}).Test(t)
+}
+
+func TestSetPolicy(t *testing.T) {
+	var failures = xycond.Failures()
+
+	xycond.SetPolicy(xycond.PolicyCount)
+	defer xycond.SetPolicy(xycond.PolicyPanic)
+
+	xycond.AssertEqual(1, 2)
+	xycond.ExpectTrue(false).Assertf("foo")
+	xycond.ExpectEqual(xycond.Failures(), failures+2).Test(t)
+
+	var buf bytes.Buffer
+	xycond.SetPolicy(xycond.PolicyLog)
+	xycond.SetLogHandler(slog.NewTextHandler(&buf, nil))
+	defer xycond.SetLogHandler(nil)
+	xycond.AssertEqual(1, 2)
+	xycond.ExpectIn("msg=\"1 != 2\"", buf.String()).Test(t)
+}
+
+func TestPolicyArgumentCheck(t *testing.T) {
+	xycond.SetPolicy(xycond.PolicyIgnore)
+	defer xycond.SetPolicy(xycond.PolicyPanic)
+
+	// Invalid arguments always panic regardless of the Policy.
+	xycond.ExpectPanic(xyerror.AssertionError, func() {
+		xycond.ExpectIn(1, 2)
+	}).Test(t)
+}
@@ -0,0 +1,391 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PrintConfig limits the output of the pretty printer.
+type PrintConfig struct {
+	// MaxDepth is the maximum level of nested values, deeper values are
+	// printed as "...".
+	MaxDepth int
+
+	// MaxLength is the maximum number of bytes printed for a string.
+	MaxLength int
+
+	// MaxElements is the maximum number of elements printed for an array,
+	// slice, map, or struct.
+	MaxElements int
+}
+
+// DefaultPrintConfig is the PrintConfig used when nothing is set.
+var DefaultPrintConfig = PrintConfig{
+	MaxDepth:    8,
+	MaxLength:   256,
+	MaxElements: 32,
+}
+
+var printConfig = DefaultPrintConfig
+var printConfigLock sync.RWMutex
+
+// SetPrintConfig changes the PrintConfig used by Sprint and failure messages.
+// A non-positive limit means no limit.
+func SetPrintConfig(cfg PrintConfig) {
+	printConfigLock.Lock()
+	defer printConfigLock.Unlock()
+	printConfig = cfg
+}
+
+// Sprint pretty prints the value as it is printed in failure messages. It
+// dereferences pointers, sorts map keys, annotates types where they are
+// ambiguous, detects cycles, and truncates the output by the PrintConfig.
+func Sprint(v any) string {
+	printConfigLock.RLock()
+	var cfg = printConfig
+	printConfigLock.RUnlock()
+	return cfg.Sprint(v)
+}
+
+// Sprint pretty prints the value with this PrintConfig.
+func (cfg PrintConfig) Sprint(v any) string {
+	var p = printer{cfg: cfg, visited: map[visit]bool{}}
+	p.print(reflect.ValueOf(v), true, 0)
+	return p.String()
+}
+
+// visit identifies a reference value which is being printed.
+type visit struct {
+	ptr uintptr
+	len int
+	typ reflect.Type
+}
+
+type printer struct {
+	strings.Builder
+	cfg     PrintConfig
+	visited map[visit]bool
+}
+
+var (
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// print writes the value, showType is true if the static type of the value is
+// unknown for readers, e.g. an element of []any.
+func (p *printer) print(v reflect.Value, showType bool, depth int) {
+	if !v.IsValid() {
+		p.WriteString("nil")
+		return
+	}
+
+	if p.printMethod(v) {
+		return
+	}
+
+	var t = v.Type()
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Float64, reflect.Complex128:
+		p.printScalar(v, showType && t.Name() != v.Kind().String())
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Complex64:
+		p.printScalar(v, showType)
+	case reflect.String:
+		if showType && t.Name() != "string" {
+			p.WriteString(t.String())
+			p.WriteString("(")
+			p.printString(v.String())
+			p.WriteString(")")
+		} else {
+			p.printString(v.String())
+		}
+	case reflect.Pointer:
+		if v.IsNil() {
+			fmt.Fprintf(p, "(%s)(nil)", t)
+			return
+		}
+		if !p.enter(v) {
+			fmt.Fprintf(p, "<cycle %s>", t)
+			return
+		}
+		defer p.leave(v)
+		p.WriteString("&")
+		p.print(v.Elem(), showType, depth)
+	case reflect.Interface:
+		p.print(v.Elem(), true, depth)
+	case reflect.Struct:
+		p.printStruct(v, depth)
+	case reflect.Map:
+		p.printMap(v, depth)
+	case reflect.Slice:
+		if v.IsNil() {
+			fmt.Fprintf(p, "%s(nil)", t)
+			return
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			fmt.Fprintf(p, "%s(", t)
+			p.printString(string(v.Bytes()))
+			p.WriteString(")")
+			return
+		}
+		if !p.enter(v) {
+			fmt.Fprintf(p, "<cycle %s>", t)
+			return
+		}
+		defer p.leave(v)
+		p.printList(v, depth)
+	case reflect.Array:
+		p.printList(v, depth)
+	default:
+		// Channels, functions, and unsafe pointers.
+		if v.IsNil() {
+			fmt.Fprintf(p, "(%s)(nil)", t)
+		} else {
+			fmt.Fprintf(p, "(%s)(%#x)", t, v.Pointer())
+		}
+	}
+}
+
+// printMethod writes the value with its Error or String method. It returns
+// false if the value doesn't have these methods or they panic.
+func (p *printer) printMethod(v reflect.Value) (ok bool) {
+	if !v.CanInterface() {
+		return false
+	}
+	var t = v.Type()
+	if !t.Implements(errorType) && !t.Implements(stringerType) {
+		return false
+	}
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return false
+	}
+
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	var s string
+	switch x := v.Interface().(type) {
+	case error:
+		s = x.Error()
+	case fmt.Stringer:
+		s = x.String()
+	}
+	p.WriteString(p.truncate(s))
+	return true
+}
+
+func (p *printer) printScalar(v reflect.Value, showType bool) {
+	var s string
+	switch v.Kind() {
+	case reflect.Bool:
+		s = strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(v.Int(), 10)
+	case reflect.Float32, reflect.Float64:
+		s = strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	case reflect.Complex64, reflect.Complex128:
+		s = strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits())
+	default:
+		s = strconv.FormatUint(v.Uint(), 10)
+	}
+	if showType {
+		fmt.Fprintf(p, "%s(%s)", v.Type(), s)
+	} else {
+		p.WriteString(s)
+	}
+}
+
+func (p *printer) printString(s string) {
+	if p.cfg.MaxLength > 0 && len(s) > p.cfg.MaxLength {
+		p.WriteString(strconv.Quote(s[:p.cfg.MaxLength]))
+		fmt.Fprintf(p, "...(%d more bytes)", len(s)-p.cfg.MaxLength)
+		return
+	}
+	p.WriteString(strconv.Quote(s))
+}
+
+func (p *printer) printStruct(v reflect.Value, depth int) {
+	var t = v.Type()
+	p.WriteString(t.String())
+	if p.tooDeep(depth) {
+		p.WriteString("{...}")
+		return
+	}
+
+	p.WriteString("{")
+	for i := 0; i < v.NumField(); i++ {
+		if i > 0 {
+			p.WriteString(", ")
+		}
+		if p.tooMany(i, v.NumField()) {
+			break
+		}
+		p.WriteString(t.Field(i).Name)
+		p.WriteString(": ")
+		p.print(v.Field(i), false, depth+1)
+	}
+	p.WriteString("}")
+}
+
+func (p *printer) printMap(v reflect.Value, depth int) {
+	var t = v.Type()
+	if v.IsNil() {
+		fmt.Fprintf(p, "%s(nil)", t)
+		return
+	}
+	if !p.enter(v) {
+		fmt.Fprintf(p, "<cycle %s>", t)
+		return
+	}
+	defer p.leave(v)
+
+	p.WriteString(t.String())
+	if p.tooDeep(depth) {
+		p.WriteString("{...}")
+		return
+	}
+
+	var showKey = t.Key().Kind() == reflect.Interface
+	var showElem = t.Elem().Kind() == reflect.Interface
+
+	var keys = v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+
+	p.WriteString("{")
+	for i := range keys {
+		if i > 0 {
+			p.WriteString(", ")
+		}
+		if p.tooMany(i, len(keys)) {
+			break
+		}
+		p.print(keys[i], showKey, depth+1)
+		p.WriteString(": ")
+		p.print(v.MapIndex(keys[i]), showElem, depth+1)
+	}
+	p.WriteString("}")
+}
+
+// lessKey orders map keys, numbers are compared by their values, other keys
+// are compared by their default formats.
+func lessKey(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+	if a.IsValid() && b.IsValid() && a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+			reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+			reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		case reflect.String:
+			return a.String() < b.String()
+		}
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+func (p *printer) printList(v reflect.Value, depth int) {
+	p.WriteString(v.Type().String())
+	if p.tooDeep(depth) {
+		p.WriteString("{...}")
+		return
+	}
+
+	var showElem = v.Type().Elem().Kind() == reflect.Interface
+	p.WriteString("{")
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			p.WriteString(", ")
+		}
+		if p.tooMany(i, v.Len()) {
+			break
+		}
+		p.print(v.Index(i), showElem, depth+1)
+	}
+	p.WriteString("}")
+}
+
+// tooDeep returns true if values at this depth shouldn't be printed.
+func (p *printer) tooDeep(depth int) bool {
+	return p.cfg.MaxDepth > 0 && depth >= p.cfg.MaxDepth
+}
+
+// tooMany writes the number of remaining elements and returns true if the
+// i-th element shouldn't be printed.
+func (p *printer) tooMany(i, n int) bool {
+	if p.cfg.MaxElements > 0 && i >= p.cfg.MaxElements {
+		fmt.Fprintf(p, "...(%d more)", n-i)
+		return true
+	}
+	return false
+}
+
+func (p *printer) truncate(s string) string {
+	if p.cfg.MaxLength > 0 && len(s) > p.cfg.MaxLength {
+		return fmt.Sprintf("%s...(%d more bytes)",
+			s[:p.cfg.MaxLength], len(s)-p.cfg.MaxLength)
+	}
+	return s
+}
+
+// enter marks the reference value as being printed, it returns false if the
+// value is already being printed, i.e. there is a cycle.
+func (p *printer) enter(v reflect.Value) bool {
+	var key = visitOf(v)
+	if p.visited[key] {
+		return false
+	}
+	p.visited[key] = true
+	return true
+}
+
+func (p *printer) leave(v reflect.Value) {
+	delete(p.visited, visitOf(v))
+}
+
+func visitOf(v reflect.Value) visit {
+	var key = visit{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+	return key
+}
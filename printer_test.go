@@ -0,0 +1,115 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+)
+
+type node struct {
+	Value int
+	next  *node
+}
+
+type color int
+
+func TestSprint(t *testing.T) {
+	var tests = []struct {
+		value any
+		want  string
+	}{
+		{nil, "nil"},
+		{1, "1"},
+		{int64(1), "int64(1)"},
+		{1.5, "1.5"},
+		{float32(1.5), "float32(1.5)"},
+		{color(2), "xycond_test.color(2)"},
+		{"foo", `"foo"`},
+		{[]byte("foo"), `[]uint8("foo")`},
+		{new(int), "&0"},
+		{(*int)(nil), "(*int)(nil)"},
+		{[]int(nil), "[]int(nil)"},
+		{[]any{1, int8(2), "a", nil}, `[]interface {}{1, int8(2), "a", nil}`},
+		{[2]uint{1, 2}, "[2]uint{1, 2}"},
+		{map[string]int{"b": 2, "a": 1}, `map[string]int{"a": 1, "b": 2}`},
+		{map[int]bool{10: true, 2: false}, "map[int]bool{2: false, 10: true}"},
+		{
+			node{Value: 1},
+			"xycond_test.node{Value: 1, next: (*xycond_test.node)(nil)}",
+		},
+		{errors.New("foo"), "foo"},
+	}
+
+	for i := range tests {
+		xycond.ExpectEqual(xycond.Sprint(tests[i].value), tests[i].want).Test(t)
+	}
+}
+
+func TestSprintCycle(t *testing.T) {
+	var n = &node{Value: 1}
+	n.next = n
+	xycond.ExpectEqual(xycond.Sprint(n),
+		"&xycond_test.node{Value: 1, next: <cycle *xycond_test.node>}").Test(t)
+
+	var m = map[string]any{}
+	m["m"] = m
+	xycond.ExpectEqual(xycond.Sprint(m),
+		`map[string]interface {}{"m": <cycle map[string]interface {}>}`).Test(t)
+
+	var s = []any{nil}
+	s[0] = s
+	xycond.ExpectEqual(xycond.Sprint(s),
+		"[]interface {}{<cycle []interface {}>}").Test(t)
+}
+
+func TestSprintLimits(t *testing.T) {
+	var cfg = xycond.PrintConfig{MaxDepth: 2, MaxLength: 3, MaxElements: 2}
+
+	xycond.ExpectEqual(cfg.Sprint("foobar"), `"foo"...(3 more bytes)`).Test(t)
+	xycond.ExpectEqual(cfg.Sprint([]int{1, 2, 3, 4}),
+		"[]int{1, 2, ...(2 more)}").Test(t)
+	xycond.ExpectEqual(cfg.Sprint([][][]int{{{1}}}),
+		"[][][]int{[][]int{[]int{...}}}").Test(t)
+
+	xycond.SetPrintConfig(cfg)
+	defer xycond.SetPrintConfig(xycond.DefaultPrintConfig)
+	xycond.ExpectEqual(xycond.Sprint(strings.Repeat("a", 4)),
+		`"aaa"...(1 more bytes)`).Test(t)
+}
+
+func TestMessageUsesSprint(t *testing.T) {
+	expectPanicMessage(t, `int64(1) != 1`, func() {
+		xycond.AssertEqual(int64(1), 1)
+	})
+	expectPanicMessage(t, `"foo" NOT IN []string{"bar"}`, func() {
+		xycond.AssertIn("foo", []string{"bar"})
+	})
+	expectPanicMessage(t, `'z' NOT IN "foo bar"`, func() {
+		xycond.AssertIn('z', "foo bar")
+	})
+	expectPanicMessage(t, `'z' NOT IN "foo bar"`, func() {
+		xycond.AssertIn("foo bar"[0]+20, "foo bar")
+	})
+}
@@ -0,0 +1,440 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package prop
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+)
+
+// Generator generates and shrinks random values of a type.
+type Generator interface {
+	// Type returns the type of generated values.
+	Type() reflect.Type
+
+	// Generate returns a random value, size is a hint of how large the value
+	// is, it grows during iterations. Values nested in slices, arrays, maps,
+	// and pointers are generated with a smaller size, so values of recursive
+	// types are finite.
+	Generate(r *rand.Rand, size int) reflect.Value
+
+	// Shrink returns smaller candidates of the value, from the smallest.
+	Shrink(v reflect.Value) []reflect.Value
+}
+
+// GeneratorOf returns a Generator of the type T. The shrink function may be
+// nil if values can't be shrunk.
+func GeneratorOf[T any](
+	generate func(r *rand.Rand, size int) T,
+	shrink func(v T) []T,
+) Generator {
+	return typedGenerator[T]{generate: generate, shrink: shrink}
+}
+
+type typedGenerator[T any] struct {
+	generate func(*rand.Rand, int) T
+	shrink   func(T) []T
+}
+
+func (g typedGenerator[T]) Type() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (g typedGenerator[T]) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(g.generate(r, size))
+}
+
+func (g typedGenerator[T]) Shrink(v reflect.Value) []reflect.Value {
+	if g.shrink == nil {
+		return nil
+	}
+	var candidates []reflect.Value
+	for _, c := range g.shrink(v.Interface().(T)) {
+		candidates = append(candidates, reflect.ValueOf(c))
+	}
+	return candidates
+}
+
+// sizeDecay is the divisor of the size of nested values.
+const sizeDecay = 4
+
+// generators generates values of any supported type by reflection, custom
+// Generators take precedence over the reflection.
+type generators map[reflect.Type]Generator
+
+// check returns an error if values of the type can't be generated, seen
+// contains types which are already checked.
+func (gs generators) check(t reflect.Type, seen map[reflect.Type]bool) error {
+	if _, ok := gs[t]; ok || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16,
+		reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64,
+		reflect.Complex128, reflect.String:
+		return nil
+	case reflect.Slice, reflect.Array, reflect.Pointer:
+		return gs.check(t.Elem(), seen)
+	case reflect.Map:
+		if err := gs.check(t.Key(), seen); err != nil {
+			return err
+		}
+		return gs.check(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := gs.check(t.Field(i).Type, seen); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("prop: no generator for type %s", t)
+}
+
+func (gs generators) generate(
+	t reflect.Type, r *rand.Rand, size int,
+) reflect.Value {
+	if g, ok := gs[t]; ok {
+		return convert(g.Generate(r, size), t)
+	}
+
+	var v = reflect.New(t).Elem()
+	var elemSize = size / sizeDecay
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		var n = r.Int63n(int64(size)*2+1) - int64(size)
+		if r.Intn(10) == 0 {
+			// Test the boundaries occasionally.
+			n = []int64{math.MinInt64, math.MaxInt64}[r.Intn(2)]
+		}
+		v.SetInt(clampInt(n, t.Bits()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		var n = uint64(r.Int63n(int64(size) + 1))
+		if r.Intn(10) == 0 {
+			n = math.MaxUint64
+		}
+		v.SetUint(clampUint(n, t.Bits()))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat((r.Float64()*2 - 1) * float64(size))
+	case reflect.Complex64, reflect.Complex128:
+		v.SetComplex(complex((r.Float64()*2-1)*float64(size),
+			(r.Float64()*2-1)*float64(size)))
+	case reflect.String:
+		var runes = make([]rune, r.Intn(size+1))
+		for i := range runes {
+			runes[i] = randomRune(r)
+		}
+		v.SetString(string(runes))
+	case reflect.Slice:
+		var n = r.Intn(size + 1)
+		v.Set(reflect.MakeSlice(t, n, n))
+		for i := 0; i < n; i++ {
+			v.Index(i).Set(gs.generate(t.Elem(), r, elemSize))
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).Set(gs.generate(t.Elem(), r, elemSize))
+		}
+	case reflect.Map:
+		v.Set(reflect.MakeMap(t))
+		for i := r.Intn(size + 1); i > 0; i-- {
+			v.SetMapIndex(gs.generate(t.Key(), r, elemSize),
+				gs.generate(t.Elem(), r, elemSize))
+		}
+	case reflect.Pointer:
+		if size > 0 && r.Intn(10) > 0 {
+			v.Set(reflect.New(t.Elem()))
+			v.Elem().Set(gs.generate(t.Elem(), r, elemSize))
+		}
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				v.Field(i).Set(gs.generate(t.Field(i).Type, r, size))
+			}
+		}
+	default:
+		// Types are checked before generating values.
+		panic(fmt.Sprintf("prop: no generator for type %s", t))
+	}
+
+	return v
+}
+
+func (gs generators) shrink(v reflect.Value) []reflect.Value {
+	var t = v.Type()
+	if g, ok := gs[t]; ok {
+		var candidates = g.Shrink(v)
+		for i := range candidates {
+			candidates[i] = convert(candidates[i], t)
+		}
+		return candidates
+	}
+
+	var candidates []reflect.Value
+	var add = func(f func(c reflect.Value)) {
+		var c = reflect.New(t).Elem()
+		f(c)
+		candidates = append(candidates, c)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			add(func(c reflect.Value) { c.SetBool(false) })
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		for _, n := range shrinkInt(v.Int()) {
+			var n = n
+			add(func(c reflect.Value) { c.SetInt(n) })
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		for _, n := range shrinkUint(v.Uint()) {
+			var n = n
+			add(func(c reflect.Value) { c.SetUint(n) })
+		}
+	case reflect.Float32, reflect.Float64:
+		var f = v.Float()
+		for _, n := range []float64{0, math.Trunc(f), f / 2} {
+			if n != f && !math.IsNaN(n) && math.Abs(n) < math.Abs(f) {
+				var n = n
+				add(func(c reflect.Value) { c.SetFloat(n) })
+			}
+		}
+	case reflect.String:
+		var runes = []rune(v.String())
+		for _, s := range shrinkList(len(runes), func(keep []int) any {
+			var result = make([]rune, len(keep))
+			for i, k := range keep {
+				result[i] = runes[k]
+			}
+			return string(result)
+		}) {
+			var s = s.(string)
+			add(func(c reflect.Value) { c.SetString(s) })
+		}
+		// Simplify characters to 'a'.
+		for i := range runes {
+			if runes[i] != 'a' {
+				var s = string(runes[:i]) + "a" + string(runes[i+1:])
+				add(func(c reflect.Value) { c.SetString(s) })
+			}
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			break
+		}
+		for _, s := range shrinkList(v.Len(), func(keep []int) any {
+			var result = reflect.MakeSlice(t, len(keep), len(keep))
+			for i, k := range keep {
+				result.Index(i).Set(v.Index(k))
+			}
+			return result
+		}) {
+			candidates = append(candidates, s.(reflect.Value))
+		}
+		candidates = append(candidates, gs.shrinkElems(v)...)
+	case reflect.Array:
+		candidates = append(candidates, gs.shrinkElems(v)...)
+	case reflect.Map:
+		if v.Len() == 0 {
+			break
+		}
+		add(func(c reflect.Value) { c.Set(reflect.MakeMap(t)) })
+		for _, key := range v.MapKeys() {
+			var key = key
+			add(func(c reflect.Value) {
+				c.Set(reflect.MakeMap(t))
+				var iter = v.MapRange()
+				for iter.Next() {
+					if iter.Key().Interface() != key.Interface() {
+						c.SetMapIndex(iter.Key(), iter.Value())
+					}
+				}
+			})
+		}
+	case reflect.Pointer:
+		if v.IsNil() {
+			break
+		}
+		add(func(c reflect.Value) {})
+		for _, e := range gs.shrink(v.Elem()) {
+			var e = e
+			add(func(c reflect.Value) {
+				c.Set(reflect.New(t.Elem()))
+				c.Elem().Set(e)
+			})
+		}
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			for _, f := range gs.shrink(v.Field(i)) {
+				var i, f = i, f
+				add(func(c reflect.Value) {
+					c.Set(v)
+					c.Field(i).Set(f)
+				})
+			}
+		}
+	}
+
+	return candidates
+}
+
+// shrinkElems returns copies of the slice or array in which one element is
+// shrunk.
+func (gs generators) shrinkElems(v reflect.Value) []reflect.Value {
+	var candidates []reflect.Value
+	for i := 0; i < v.Len(); i++ {
+		for _, e := range gs.shrink(v.Index(i)) {
+			var c = reflect.New(v.Type()).Elem()
+			if v.Kind() == reflect.Slice {
+				c.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+				reflect.Copy(c, v)
+			} else {
+				c.Set(v)
+			}
+			c.Index(i).Set(e)
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates
+}
+
+// shrinkList returns smaller lists of n elements built by the function, which
+// receives indexes of kept elements: the empty list, each half, and lists
+// without one element.
+func shrinkList(n int, build func(keep []int) any) []any {
+	if n == 0 {
+		return nil
+	}
+
+	var indexes = func(from, to, skip int) []int {
+		var result []int
+		for i := from; i < to; i++ {
+			if i != skip {
+				result = append(result, i)
+			}
+		}
+		return result
+	}
+
+	var candidates = []any{build(nil)}
+	if n > 2 {
+		candidates = append(candidates,
+			build(indexes(0, n/2, -1)), build(indexes(n/2, n, -1)))
+	}
+	if n > 1 {
+		for i := 0; i < n; i++ {
+			candidates = append(candidates, build(indexes(0, n, i)))
+		}
+	}
+	return candidates
+}
+
+func shrinkInt(n int64) []int64 {
+	if n == 0 {
+		return nil
+	}
+	var candidates = []int64{0}
+	if n < 0 && n != math.MinInt64 {
+		candidates = append(candidates, -n)
+	}
+	if half := n / 2; half != 0 {
+		candidates = append(candidates, half)
+	}
+	if n > 0 {
+		candidates = append(candidates, n-1)
+	} else {
+		candidates = append(candidates, n+1)
+	}
+	return candidates
+}
+
+func shrinkUint(n uint64) []uint64 {
+	if n == 0 {
+		return nil
+	}
+	var candidates = []uint64{0}
+	if half := n / 2; half != 0 {
+		candidates = append(candidates, half)
+	}
+	return append(candidates, n-1)
+}
+
+func clampInt(n int64, bits int) int64 {
+	var max = int64(1)<<(bits-1) - 1
+	if bits == 64 {
+		max = math.MaxInt64
+	}
+	switch {
+	case n > max:
+		return max
+	case n < -max-1:
+		return -max - 1
+	}
+	return n
+}
+
+func clampUint(n uint64, bits int) uint64 {
+	if bits < 64 && n > uint64(1)<<bits-1 {
+		return uint64(1)<<bits - 1
+	}
+	return n
+}
+
+// randomRune returns a printable ASCII rune usually, or any valid rune
+// sometimes.
+func randomRune(r *rand.Rand) rune {
+	if r.Intn(10) > 0 {
+		return rune(' ' + r.Intn('~'-' '+1))
+	}
+	for {
+		var c = rune(r.Int31n(0x10FFFF + 1))
+		if c < 0xD800 || c > 0xDFFF {
+			return c
+		}
+	}
+}
+
+// convert converts the value generated by a custom Generator to the type.
+func convert(v reflect.Value, t reflect.Type) reflect.Value {
+	if !v.IsValid() {
+		return reflect.Zero(t)
+	}
+	return v.Convert(t)
+}
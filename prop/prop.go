@@ -0,0 +1,195 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package prop supports property-based testing with xycond Conditions.
+//
+// A property is a function which receives random inputs and returns a
+// Condition. When the Condition is false, the inputs are shrunk to a minimal
+// counterexample which is reported with the failure message of the Condition.
+package prop
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xybor-x/xycond"
+)
+
+// T instances may be *testing.T or *testing.B.
+type T interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Config configures how properties are checked.
+type Config struct {
+	// Iterations is the number of random inputs to check.
+	Iterations int
+
+	// MaxSize is the maximum size hint passed to Generators, the size grows
+	// from zero to MaxSize during iterations.
+	MaxSize int
+
+	// MaxShrinks is the maximum number of successful shrinking steps.
+	MaxShrinks int
+
+	// Seed is the seed of the random source. If it is zero, the environment
+	// variable XYCOND_SEED or the current time is used.
+	Seed int64
+
+	// Generators are used instead of the reflection for their types.
+	Generators []Generator
+}
+
+// DefaultConfig is the Config used by ForAll.
+var DefaultConfig = Config{Iterations: 100, MaxSize: 100, MaxShrinks: 1000}
+
+var conditionType = reflect.TypeOf(xycond.Condition{})
+
+// ForAll checks the property with DefaultConfig. The property must be a
+// function returning an xycond.Condition, its parameters are generated by
+// the reflection.
+func ForAll(t T, property any) {
+	t.Helper()
+	DefaultConfig.ForAll(t, property)
+}
+
+// ForAll checks the property with this Config. A failure reports the seed to
+// reproduce it, the original and the shrunk inputs, and the failure message of
+// the shrunk inputs.
+func (cfg Config) ForAll(t T, property any) {
+	t.Helper()
+
+	var f = reflect.ValueOf(property)
+	var ft = f.Type()
+	if ft.Kind() != reflect.Func || ft.NumOut() != 1 ||
+		ft.Out(0) != conditionType || ft.IsVariadic() {
+		xycond.Panicf("prop: property must be a function returning "+
+			"xycond.Condition, but got %s", ft)
+	}
+
+	var gs = generators{}
+	for _, g := range cfg.Generators {
+		gs[g.Type()] = g
+	}
+
+	var seen = map[reflect.Type]bool{}
+	for i := 0; i < ft.NumIn(); i++ {
+		if err := gs.check(ft.In(i), seen); err != nil {
+			t.Errorf("%s", err)
+			return
+		}
+	}
+
+	var seed = cfg.seed()
+	var r = rand.New(rand.NewSource(seed))
+	var args = make([]reflect.Value, ft.NumIn())
+
+	for i := 0; i < cfg.Iterations; i++ {
+		var size = 0
+		if cfg.Iterations > 1 {
+			size = i * cfg.MaxSize / (cfg.Iterations - 1)
+		}
+		for j := range args {
+			args[j] = gs.generate(ft.In(j), r, size)
+		}
+
+		if c := check(f, args); !c.OK() {
+			var original = sprintArgs(args)
+			var shrunk, steps = cfg.shrink(gs, f, args)
+			t.Errorf("property failed after %d iterations "+
+				"(seed %d, rerun with XYCOND_SEED=%d)\n"+
+				"original input:%s\nshrunk input (%d steps):%s\n%s",
+				i+1, seed, seed, original, steps, sprintArgs(shrunk),
+				check(f, shrunk).Message())
+			return
+		}
+	}
+}
+
+func (cfg Config) seed() int64 {
+	if cfg.Seed != 0 {
+		return cfg.Seed
+	}
+	var seed, err = strconv.ParseInt(os.Getenv("XYCOND_SEED"), 10, 64)
+	if err == nil && seed != 0 {
+		return seed
+	}
+	return time.Now().UnixNano()
+}
+
+// shrink shrinks the failed inputs one by one, a candidate is accepted if the
+// property still fails with it.
+func (cfg Config) shrink(
+	gs generators, f reflect.Value, args []reflect.Value,
+) ([]reflect.Value, int) {
+	var current = append([]reflect.Value(nil), args...)
+	var steps = 0
+
+	for steps < cfg.MaxShrinks {
+		var shrunk = false
+		for i := 0; i < len(current) && !shrunk; i++ {
+			for _, c := range gs.shrink(current[i]) {
+				if reflect.DeepEqual(c.Interface(), current[i].Interface()) {
+					continue
+				}
+
+				var candidate = append([]reflect.Value(nil), current...)
+				candidate[i] = c
+				if !check(f, candidate).OK() {
+					current = candidate
+					shrunk = true
+					steps++
+					break
+				}
+			}
+		}
+		if !shrunk {
+			break
+		}
+	}
+
+	return current, steps
+}
+
+// check calls the property, a panic is reported as a false Condition.
+func check(f reflect.Value, args []reflect.Value) (c xycond.Condition) {
+	defer func() {
+		if r := recover(); r != nil {
+			c = xycond.NewCondition(false, func() string {
+				return fmt.Sprintf("property panicked: %s", xycond.Sprint(r))
+			})
+		}
+	}()
+	return f.Call(args)[0].Interface().(xycond.Condition)
+}
+
+func sprintArgs(args []reflect.Value) string {
+	var sb strings.Builder
+	for i := range args {
+		fmt.Fprintf(&sb, "\n  #%d: %s", i, xycond.Sprint(args[i].Interface()))
+	}
+	return sb.String()
+}
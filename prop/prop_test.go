@@ -0,0 +1,190 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package prop_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+	"github.com/xybor-x/xycond/prop"
+	"github.com/xybor-x/xyerror"
+)
+
+type mockT struct {
+	failures []string
+}
+
+func (*mockT) Helper() {}
+
+func (m *mockT) Errorf(format string, args ...any) {
+	m.failures = append(m.failures, fmt.Sprintf(format, args...))
+}
+
+type point struct {
+	X, Y int
+	tag  string
+}
+
+func TestForAllPass(t *testing.T) {
+	prop.ForAll(t, func(s []int) xycond.Condition {
+		var sorted = append([]int(nil), s...)
+		sort.Ints(sorted)
+		return xycond.ExpectEqual(len(sorted), len(s))
+	})
+
+	prop.ForAll(t, func(
+		p *point, m map[string]bool, a [2]uint8,
+	) xycond.Condition {
+		return xycond.ExpectTrue(p == nil || p.tag == "")
+	})
+}
+
+func TestForAllShrinkInt(t *testing.T) {
+	var m = &mockT{}
+	var cfg = prop.DefaultConfig
+	cfg.Seed = 1
+	cfg.ForAll(m, func(x int) xycond.Condition {
+		return xycond.ExpectLessThan(x, 10)
+	})
+
+	xycond.ExpectEqual(len(m.failures), 1).Test(t)
+	xycond.ExpectIn("seed 1, rerun with XYCOND_SEED=1", m.failures[0]).Test(t)
+	xycond.ExpectIn("shrunk input", m.failures[0]).Test(t)
+	xycond.ExpectIn("#0: 10\n10 is not less than 10", m.failures[0]).Test(t)
+}
+
+func TestForAllShrinkSlice(t *testing.T) {
+	var m = &mockT{}
+	var cfg = prop.DefaultConfig
+	cfg.Seed = 2
+	cfg.ForAll(m, func(s []string) xycond.Condition {
+		return xycond.ExpectNotIn("x", strings.Join(s, ""))
+	})
+
+	xycond.ExpectEqual(len(m.failures), 1).Test(t)
+	xycond.ExpectIn(`#0: []string{"x"}`, m.failures[0]).Test(t)
+}
+
+func TestForAllStruct(t *testing.T) {
+	var m = &mockT{}
+	var cfg = prop.DefaultConfig
+	cfg.Seed = 3
+	cfg.ForAll(m, func(p point) xycond.Condition {
+		return xycond.ExpectLessThan(p.X+p.Y, 5)
+	})
+
+	xycond.ExpectEqual(len(m.failures), 1).Test(t)
+	xycond.ExpectIn("5 is not less than 5", m.failures[0]).Test(t)
+}
+
+func TestForAllPanic(t *testing.T) {
+	var m = &mockT{}
+	var cfg = prop.DefaultConfig
+	cfg.Seed = 4
+	cfg.ForAll(m, func(s []int) xycond.Condition {
+		_ = s[2]
+		return xycond.ExpectTrue(true)
+	})
+
+	xycond.ExpectEqual(len(m.failures), 1).Test(t)
+	xycond.ExpectIn("#0: []int{}", m.failures[0]).Test(t)
+	xycond.ExpectIn("property panicked: runtime error", m.failures[0]).Test(t)
+}
+
+func TestForAllGenerator(t *testing.T) {
+	var even = prop.GeneratorOf(func(r *rand.Rand, size int) int {
+		return r.Intn(size+1) * 2
+	}, func(v int) []int {
+		if v == 0 {
+			return nil
+		}
+		return []int{0, v / 4 * 2}
+	})
+
+	var cfg = prop.DefaultConfig
+	cfg.Generators = []prop.Generator{even}
+	cfg.ForAll(t, func(x int) xycond.Condition {
+		return xycond.ExpectZero(x % 2)
+	})
+
+	var m = &mockT{}
+	cfg.Seed = 5
+	cfg.ForAll(m, func(x int) xycond.Condition {
+		return xycond.ExpectLessThan(x, 7)
+	})
+	xycond.ExpectEqual(len(m.failures), 1).Test(t)
+	xycond.ExpectIn("#0: 8\n", m.failures[0]).Test(t)
+}
+
+func TestForAllSeedEnv(t *testing.T) {
+	t.Setenv("XYCOND_SEED", "42")
+
+	var m = &mockT{}
+	prop.ForAll(m, func(x int) xycond.Condition {
+		return xycond.ExpectEqual(x, 0)
+	})
+	xycond.ExpectEqual(len(m.failures), 1).Test(t)
+	xycond.ExpectIn("seed 42,", m.failures[0]).Test(t)
+}
+
+func TestForAllInvalid(t *testing.T) {
+	xycond.ExpectPanic(xyerror.AssertionError, func() {
+		prop.ForAll(t, func(x int) bool { return true })
+	}).Test(t)
+}
+
+type tree struct {
+	Kids []tree
+	Next *tree
+}
+
+func (tr tree) count() int {
+	var n = 1
+	for _, k := range tr.Kids {
+		n += k.count()
+	}
+	if tr.Next != nil {
+		n += tr.Next.count()
+	}
+	return n
+}
+
+func TestForAllRecursive(t *testing.T) {
+	var cfg = prop.DefaultConfig
+	cfg.Seed = 6
+	cfg.ForAll(t, func(tr tree, m map[string]map[string]int) xycond.Condition {
+		return xycond.ExpectLessThan(tr.count(), 100000)
+	})
+}
+
+func TestForAllUnsupported(t *testing.T) {
+	var m = &mockT{}
+	prop.ForAll(m, func(x int, s struct{ C []chan int }) xycond.Condition {
+		return xycond.ExpectTrue(true)
+	})
+	xycond.ExpectEqual(len(m.failures), 1).Test(t)
+	xycond.ExpectEqual(m.failures[0],
+		"prop: no generator for type chan int").Test(t)
+}
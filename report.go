@@ -0,0 +1,124 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// ReportValue is a checked value in a FailureReport.
+type ReportValue struct {
+	// Pretty is the value printed by Sprint.
+	Pretty string `json:"pretty"`
+
+	// Raw is the JSON encoding of the value, it is omitted if the value can't
+	// be encoded.
+	Raw json.RawMessage `json:"raw,omitempty"`
+}
+
+// FailureReport is the machine-readable form of a FailureEvent.
+type FailureReport struct {
+	Op      string        `json:"op"`
+	Message string        `json:"message"`
+	Params  []ReportValue `json:"params,omitempty"`
+	File    string        `json:"file,omitempty"`
+	Line    int           `json:"line,omitempty"`
+	Test    string        `json:"test,omitempty"`
+	Time    time.Time     `json:"time"`
+}
+
+// NewFailureReport returns the FailureReport of the event at the current
+// time.
+func NewFailureReport(e FailureEvent) FailureReport {
+	return FailureReport{
+		Op:      e.Op,
+		Message: e.Message,
+		Params:  reportValues(e.Params),
+		File:    e.File,
+		Line:    e.Line,
+		Test:    e.Test,
+		Time:    time.Now(),
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c Condition) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Op      string        `json:"op"`
+		Result  bool          `json:"result"`
+		Params  []ReportValue `json:"params,omitempty"`
+		Message string        `json:"message,omitempty"`
+	}{
+		Op:      c.op.String(),
+		Result:  c.result,
+		Params:  reportValues(c.values()),
+		Message: c.Message(),
+	})
+}
+
+func reportValues(values []any) []ReportValue {
+	if len(values) == 0 {
+		return nil
+	}
+
+	var result = make([]ReportValue, len(values))
+	for i := range values {
+		result[i].Pretty = Sprint(values[i])
+		// Errors are usually encoded as empty objects, which are misleading.
+		if _, ok := values[i].(error); ok {
+			continue
+		}
+		if raw, err := json.Marshal(values[i]); err == nil {
+			result[i].Raw = raw
+		}
+	}
+	return result
+}
+
+var reportFileLock sync.Mutex
+
+// writeReport appends the FailureReport of the event as a JSON line to the
+// file in the environment variable XYCOND_REPORT, if it is set.
+func writeReport(e FailureEvent) {
+	var path = os.Getenv("XYCOND_REPORT")
+	if path == "" {
+		return
+	}
+
+	var data, err = json.Marshal(NewFailureReport(e))
+	if err != nil {
+		return
+	}
+
+	reportFileLock.Lock()
+	defer reportFileLock.Unlock()
+
+	var f *os.File
+	f, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(append(data, '\n'))
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package report writes failures of xycond Conditions as JUnit XML or TAP
+// version 13 documents.
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/xybor-x/xycond"
+)
+
+// unknownTest is the group name of failures which don't belong to any test,
+// e.g. failures of Assert.
+const unknownTest = "(unknown)"
+
+// Collector collects failures of Conditions. Only tests having failures are
+// known by the Collector.
+type Collector struct {
+	lock     sync.Mutex
+	failures []xycond.FailureEvent
+	remove   func()
+}
+
+// NewCollector returns a Collector which collects every failure delivered by
+// xycond.OnFailure until it is closed.
+func NewCollector() *Collector {
+	var c = &Collector{}
+	c.remove = xycond.OnFailure(c.Add)
+	return c
+}
+
+// Close stops collecting failures.
+func (c *Collector) Close() {
+	if c.remove != nil {
+		c.remove()
+	}
+}
+
+// Add adds a failure to the Collector. It can be used as a failure hook of an
+// xycond.Asserter.
+func (c *Collector) Add(e xycond.FailureEvent) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.failures = append(c.failures, e)
+}
+
+// Failures returns the collected failures.
+func (c *Collector) Failures() []xycond.FailureEvent {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]xycond.FailureEvent(nil), c.failures...)
+}
+
+// testGroup contains failures of a test.
+type testGroup struct {
+	name     string
+	failures []xycond.FailureEvent
+}
+
+// groups returns the collected failures grouped by test name, in the order
+// of their first failures.
+func (c *Collector) groups() []testGroup {
+	var groups []testGroup
+	var index = map[string]int{}
+	for _, e := range c.Failures() {
+		var name = e.Test
+		if name == "" {
+			name = unknownTest
+		}
+		var i, ok = index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, testGroup{name: name})
+		}
+		groups[i].failures = append(groups[i].failures, e)
+	}
+	return groups
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name     string         `xml:"name,attr"`
+	Failures []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the collected failures as a JUnit XML document, in which
+// each test is a testcase of the suite and each failure is a failure element.
+func (c *Collector) WriteJUnit(w io.Writer, suite string) error {
+	var groups = c.groups()
+	var s = junitSuite{Name: suite, Tests: len(groups), Failures: len(groups)}
+	for _, g := range groups {
+		var tc = junitCase{Name: g.name}
+		for _, e := range g.failures {
+			tc.Failures = append(tc.Failures, junitFailure{
+				Message: e.Message,
+				Type:    e.Op,
+				Text:    location(e) + e.Message,
+			})
+		}
+		s.Cases = append(s.Cases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	var enc = xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitSuites{Suites: []junitSuite{s}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteTAP writes the collected failures as a TAP version 13 document, in
+// which each test is a not ok test point with its failures in the YAML block.
+func (c *Collector) WriteTAP(w io.Writer) error {
+	var groups = c.groups()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "TAP version 13\n1..%d\n", len(groups))
+	for i, g := range groups {
+		fmt.Fprintf(&sb, "not ok %d - %s\n", i+1, g.name)
+		sb.WriteString("  ---\n  failures:\n")
+		for _, e := range g.failures {
+			fmt.Fprintf(&sb, "    - op: %s\n", strconv.Quote(e.Op))
+			fmt.Fprintf(&sb, "      message: %s\n", strconv.Quote(e.Message))
+			if e.File != "" {
+				fmt.Fprintf(&sb, "      at: %s\n",
+					strconv.Quote(fmt.Sprintf("%s:%d", e.File, e.Line)))
+			}
+		}
+		sb.WriteString("  ...\n")
+	}
+
+	var _, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// location returns the "file:line: " prefix of the failure, or an empty string
+// if the location is unknown.
+func location(e xycond.FailureEvent) string {
+	if e.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d: ", e.File, e.Line)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+	"github.com/xybor-x/xycond/report"
+)
+
+type mocktest string
+
+func (mocktest) Fail() {}
+
+func (n mocktest) Name() string { return string(n) }
+
+func newCollector() *report.Collector {
+	var c = &report.Collector{}
+	c.Add(xycond.FailureEvent{Op: "equal", Message: "1 != 2",
+		File: "foo_test.go", Line: 10, Test: "TestFoo"})
+	c.Add(xycond.FailureEvent{Op: "in", Message: `"a" NOT IN "b<c>"`,
+		File: "bar_test.go", Line: 20, Test: "TestBar"})
+	c.Add(xycond.FailureEvent{Op: "true", Message: "expect true, but got false",
+		File: "foo_test.go", Line: 12, Test: "TestFoo"})
+	c.Add(xycond.FailureEvent{Op: "nil", Message: "expect a nil value"})
+	return c
+}
+
+func TestCollector(t *testing.T) {
+	var c = report.NewCollector()
+	xycond.ExpectEqual(1, 2).Test(mocktest("TestFoo"))
+	c.Close()
+	xycond.ExpectEqual(1, 2).Test(mocktest("TestBar"))
+
+	var failures = c.Failures()
+	xycond.ExpectEqual(len(failures), 1).Test(t)
+	xycond.ExpectEqual(failures[0].Test, "TestFoo").Test(t)
+	xycond.ExpectEqual(failures[0].Message, "1 != 2").Test(t)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	xycond.ExpectNil(newCollector().WriteJUnit(&buf, "xycond")).Test(t)
+	xycond.ExpectSnapshot(t, "junit", buf.String()).Test(t)
+}
+
+func TestWriteTAP(t *testing.T) {
+	var buf bytes.Buffer
+	xycond.ExpectNil(newCollector().WriteTAP(&buf)).Test(t)
+	xycond.ExpectSnapshot(t, "tap", buf.String()).Test(t)
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+)
+
+func TestConditionMarshalJSON(t *testing.T) {
+	var data, err = json.Marshal(xycond.ExpectEqual([]int{1}, 2))
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(string(data), `{"op":"equal","result":false,"params":[`+
+		`{"pretty":"[]int{1}","raw":[1]},{"pretty":"2","raw":2}],`+
+		`"message":"[]int{1} != 2"}`).Test(t)
+
+	data, err = json.Marshal(xycond.ExpectNil(errors.New("foo")))
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(string(data), `{"op":"nil","result":false,"params":[`+
+		`{"pretty":"foo"}],"message":"expect a nil value, but got foo"}`).
+		Test(t)
+
+	data, err = json.Marshal(xycond.ExpectTrue(true))
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(string(data), `{"op":"true","result":true}`).Test(t)
+}
+
+func TestReportFile(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "report.jsonl")
+	t.Setenv("XYCOND_REPORT", path)
+
+	xycond.ExpectEqual(1, 2).Test(mocktest{"TestFoo"})
+	xycond.ExpectEqual(1, 1).Test(mocktest{"TestFoo"})
+	xycond.ExpectTrue(false).Test(mocktest{})
+
+	var f, err = os.Open(path)
+	xycond.ExpectNil(err).Test(t)
+	defer f.Close()
+
+	var reports []xycond.FailureReport
+	var scanner = bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r xycond.FailureReport
+		xycond.ExpectNil(json.Unmarshal(scanner.Bytes(), &r)).Test(t)
+		reports = append(reports, r)
+	}
+
+	xycond.ExpectEqual(len(reports), 2).Test(t)
+	xycond.ExpectEqual(reports[0].Op, "equal").Test(t)
+	xycond.ExpectEqual(reports[0].Test, "TestFoo").Test(t)
+	xycond.ExpectEqual(reports[0].Message, "1 != 2").Test(t)
+	xycond.ExpectEqual(string(reports[0].Params[1].Raw), "2").Test(t)
+	xycond.ExpectEqual(filepath.Base(reports[0].File), "report_test.go").
+		Test(t)
+	xycond.ExpectFalse(reports[0].Time.IsZero()).Test(t)
+	xycond.ExpectEqual(reports[1].Op, "true").Test(t)
+	xycond.ExpectEqual(reports[1].Test, "").Test(t)
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SnapshotDir is the directory containing golden files.
+const SnapshotDir = "testdata"
+
+// snapshotExt is the extension of golden files.
+const snapshotExt = ".golden"
+
+// usedSnapshots contains paths of golden files compared in this process.
+var usedSnapshots = map[string]bool{}
+var usedSnapshotsLock sync.Mutex
+
+func init() {
+	// The flag is only registered in test binaries, so it doesn't show up in
+	// other programs importing this package.
+	if isTestBinary() && flag.Lookup("update") == nil {
+		flag.Bool("update", false, "rewrite golden files of ExpectSnapshot")
+	}
+}
+
+// isTestBinary returns true if the program is built by go test, which names
+// it <package>.test.
+func isTestBinary() bool {
+	var name = strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	return strings.HasSuffix(name, ".test")
+}
+
+// namer instances may be *testing.T or *testing.B.
+type namer interface {
+	Name() string
+}
+
+// ExpectSnapshot returns a true Condition if the serialized value matches the
+// golden file testdata/<Test>/<name>.golden.
+//
+// Strings and byte slices are stored as they are, other values are stored as
+// indented JSON, or as Go-syntax representation if they can't be encoded.
+//
+// The golden file is rewritten instead of compared if the environment variable
+// XYCOND_UPDATE is true or the -update flag is set. The flag is registered in
+// test binaries, so test packages must not define their own -update flag.
+func ExpectSnapshot(t namer, name string, value any) Condition {
+	var path = filepath.Join(SnapshotDir,
+		filepath.FromSlash(t.Name()), name+snapshotExt)
+	var got = serializeSnapshot(value)
+
+	usedSnapshotsLock.Lock()
+	usedSnapshots[path] = true
+	usedSnapshotsLock.Unlock()
+
+	var detail string
+	if isUpdatingSnapshots() {
+		detail = updateSnapshot(path, got)
+	} else {
+		detail = compareSnapshot(path, got)
+	}
+	if detail == "" {
+		return newCondition(true, opSnapshot)
+	}
+	return newCondition(false, opSnapshot, path, detail)
+}
+
+// updateSnapshot rewrites the golden file, it returns the error message if it
+// fails.
+func updateSnapshot(path, got string) string {
+	var err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err == nil {
+		err = os.WriteFile(path, []byte(got), 0o644)
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// compareSnapshot compares the golden file with the serialized value, it
+// returns why they are mismatched, or an empty string if they match. The diff
+// is only computed if they are mismatched.
+func compareSnapshot(path, got string) string {
+	var want, err = os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return "no such snapshot, " +
+			"run with -update or XYCOND_UPDATE=1 to create it"
+	case err != nil:
+		return err.Error()
+	case string(want) != got:
+		return "mismatched snapshot\n" +
+			unifiedDiff(path, "got", string(want), got)
+	}
+	return ""
+}
+
+// ObsoleteSnapshots returns golden files in the directory which weren't
+// compared by ExpectSnapshot in this process. It is meaningful only if it is
+// called after all tests ran, e.g. in TestMain after m.Run. The directory may
+// be relative to the working directory or absolute.
+func ObsoleteSnapshots(dir string) ([]string, error) {
+	usedSnapshotsLock.Lock()
+	defer usedSnapshotsLock.Unlock()
+
+	// Paths are compared as absolute paths, the used ones are relative to the
+	// working directory.
+	var used = make(map[string]bool, len(usedSnapshots))
+	for path := range usedSnapshots {
+		if abs, err := filepath.Abs(path); err == nil {
+			used[abs] = true
+		}
+	}
+
+	var obsolete []string
+	var err = filepath.WalkDir(dir,
+		func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || filepath.Ext(path) != snapshotExt {
+				return nil
+			}
+			if abs, err := filepath.Abs(path); err != nil || !used[abs] {
+				obsolete = append(obsolete, path)
+			}
+			return nil
+		})
+
+	sort.Strings(obsolete)
+	return obsolete, err
+}
+
+// isUpdatingSnapshots returns true if golden files should be rewritten.
+func isUpdatingSnapshots() bool {
+	if v, err := strconv.ParseBool(os.Getenv("XYCOND_UPDATE")); err == nil {
+		return v
+	}
+	if f := flag.Lookup("update"); f != nil {
+		var v, err = strconv.ParseBool(f.Value.String())
+		return err == nil && v
+	}
+	return false
+}
+
+func serializeSnapshot(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case json.RawMessage:
+		var buf bytes.Buffer
+		if json.Indent(&buf, v, "", "  ") == nil {
+			buf.WriteString("\n")
+			return buf.String()
+		}
+		return string(v)
+	}
+
+	if data, err := json.MarshalIndent(value, "", "  "); err == nil {
+		return string(data) + "\n"
+	}
+	return fmt.Sprintf("%#v\n", value)
+}
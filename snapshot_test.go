@@ -0,0 +1,102 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+)
+
+func TestExpectSnapshot(t *testing.T) {
+	t.Setenv("XYCOND_UPDATE", "0")
+
+	xycond.ExpectSnapshot(t, "string", "foo\nbar\n").Test(t)
+	xycond.ExpectSnapshot(t, "json", map[string]int{"a": 1, "b": 2}).Test(t)
+	xycond.ExpectSnapshot(t, "string", "foo\nbuzz\n").
+		True(t.Fail).
+		False(func() {})
+	xycond.ExpectSnapshot(t, "missing", "foo").True(t.Fail)
+}
+
+func TestExpectSnapshotMessage(t *testing.T) {
+	t.Setenv("XYCOND_UPDATE", "0")
+
+	xycond.ExpectSnapshot(t, "lines", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n").
+		Test(t)
+
+	defer func() {
+		var msg = recover().(error).Error()
+		xycond.ExpectIn("@@ -3,7 +3,7 @@\n 3\n 4\n 5\n-6\n+six\n 7\n", msg).
+			Test(t)
+	}()
+	xycond.ExpectSnapshot(t, "lines", "1\n2\n3\n4\n5\nsix\n7\n8\n9\n10\n").
+		Assert("")
+}
+
+func TestExpectSnapshotUpdate(t *testing.T) {
+	var name = mocktest{"TestExpectSnapshotUpdate/sub"}
+	var dir = filepath.Join(xycond.SnapshotDir, "TestExpectSnapshotUpdate")
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	t.Setenv("XYCOND_UPDATE", "1")
+	xycond.ExpectSnapshot(name, "bytes", []byte("foo")).Test(t)
+
+	var data, err = os.ReadFile(filepath.Join(dir, "sub", "bytes.golden"))
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(string(data), "foo").Test(t)
+
+	t.Setenv("XYCOND_UPDATE", "0")
+	xycond.ExpectSnapshot(name, "bytes", "foo").Test(t)
+}
+
+func TestObsoleteSnapshots(t *testing.T) {
+	var name = mocktest{"TestObsoleteSnapshots"}
+	var dir = filepath.Join(xycond.SnapshotDir, "TestObsoleteSnapshots")
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	t.Setenv("XYCOND_UPDATE", "1")
+	xycond.ExpectSnapshot(name, "used", "foo").Test(t)
+	xycond.ExpectNil(os.WriteFile(
+		filepath.Join(dir, "unused.golden"), nil, 0o644)).Test(t)
+
+	var obsolete, err = xycond.ObsoleteSnapshots(dir)
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(len(obsolete), 1).Test(t)
+	xycond.ExpectTrue(strings.HasSuffix(obsolete[0], "unused.golden")).Test(t)
+
+	var abs, _ = filepath.Abs(dir)
+	obsolete, err = xycond.ObsoleteSnapshots(abs)
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(len(obsolete), 1).Test(t)
+	xycond.ExpectEqual(obsolete[0], filepath.Join(abs, "unused.golden")).
+		Test(t)
+}
+
+func TestSnapshotUpdateFlag(t *testing.T) {
+	var f = flag.Lookup("update")
+	xycond.ExpectNotNil(f).Test(t)
+	xycond.ExpectEqual(f.DefValue, "false").Test(t)
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// packagePath is the import path of this package.
+const packagePath = "github.com/xybor-x/xycond"
+
+// callSite is the location where a Condition function is called.
+type callSite struct {
+	file string
+	line int
+	fn   string
+}
+
+// sourceExprs caches the source expression of call sites.
+var sourceExprs sync.Map
+
+// sourceFiles caches the parsed source files by their paths, so call sites in
+// the same file don't parse it again. A file which can't be parsed is cached as
+// a nil *sourceFile.
+var sourceFiles sync.Map
+
+type sourceFile struct {
+	fset *token.FileSet
+	file *ast.File
+}
+
+// parseSource returns the parsed source file at the path, or nil if it is
+// unavailable.
+func parseSource(path string) *sourceFile {
+	if src, ok := sourceFiles.Load(path); ok {
+		return src.(*sourceFile)
+	}
+
+	var src = &sourceFile{fset: token.NewFileSet()}
+	var err error
+	src.file, err = parser.ParseFile(src.fset, path, nil, 0)
+	if err != nil {
+		src = nil
+	}
+	var actual, _ = sourceFiles.LoadOrStore(path, src)
+	return actual.(*sourceFile)
+}
+
+// callerSite returns the call site of the function fn, skip is the number of
+// stack frames to ascend from the caller of callerSite.
+func callerSite(skip int, fn string) callSite {
+	var _, file, line, _ = runtime.Caller(skip + 1)
+	return callSite{file: file, line: line, fn: fn}
+}
+
+// expr returns the source of the first argument passed to the function at the
+// call site, or an empty string if the source is unavailable. Only the source
+// is returned, values of the operands can't be recovered after the call.
+func (s callSite) expr() string {
+	if s.file == "" {
+		return ""
+	}
+	if expr, ok := sourceExprs.Load(s); ok {
+		return expr.(string)
+	}
+
+	var expr = s.parseExpr()
+	sourceExprs.Store(s, expr)
+	return expr
+}
+
+func (s callSite) parseExpr() string {
+	var src = parseSource(s.file)
+	if src == nil {
+		return ""
+	}
+	var fset, file = src.fset, src.file
+
+	// Find the innermost call of the function which covers the line.
+	var found *ast.CallExpr
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		if fset.Position(n.Pos()).Line > s.line ||
+			fset.Position(n.End()).Line < s.line {
+			return false
+		}
+		if call, ok := n.(*ast.CallExpr); ok && len(call.Args) > 0 &&
+			calleeName(call.Fun) == s.fn {
+			found = call
+		}
+		return true
+	})
+	if found == nil {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if format.Node(&buf, fset, found.Args[0]) != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// calleeName returns the name of the called function, without the package or
+// receiver.
+func calleeName(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	case *ast.IndexExpr:
+		return calleeName(f.X)
+	}
+	return ""
+}
+
+// externalCaller returns the first stack frame outside this package.
+func externalCaller() runtime.Frame {
+	var pcs [32]uintptr
+	var n = runtime.Callers(2, pcs[:])
+	var frames = runtime.CallersFrames(pcs[:n])
+	for {
+		var frame, more = frames.Next()
+		if !isInternalFunc(frame.Function) {
+			return frame
+		}
+		if !more {
+			return runtime.Frame{}
+		}
+	}
+}
+
+// isInternalFunc returns true if the function is defined in this package or
+// its subpackages, except their external test packages.
+func isInternalFunc(name string) bool {
+	var slash = strings.LastIndexByte(name, '/')
+	var pkg, _, _ = strings.Cut(name[slash+1:], ".")
+	pkg = name[:slash+1] + pkg
+	return (pkg == packagePath || strings.HasPrefix(pkg, packagePath+"/")) &&
+		!strings.HasSuffix(pkg, "_test")
+}
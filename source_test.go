@@ -0,0 +1,64 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"testing"
+
+	"github.com/xybor-x/xycond"
+)
+
+type user struct {
+	Age    int
+	Active bool
+}
+
+func TestExpressionCapture(t *testing.T) {
+	var u = user{Age: 20, Active: false}
+
+	expectPanicMessage(t,
+		"expect u.Age > 18 && u.Active to be true, but got false", func() {
+			xycond.AssertTrue(u.Age > 18 && u.Active)
+		})
+	expectPanicMessage(t,
+		"expect u.Age > 18 to be false, but got true", func() {
+			xycond.AssertFalse(u.Age > 18)
+		})
+	expectPanicMessage(t,
+		"expect u.Active to be true, but got false", func() {
+			xycond.ExpectTrue(
+				u.Active,
+			).Assert("")
+		})
+
+	// The cached expression is reused for the same call site.
+	for i := 0; i < 2; i++ {
+		expectPanicMessage(t, "expect i < 0 to be true, but got false",
+			func() { xycond.ExpectTrue(i < 0).Assert("") })
+	}
+}
+
+func TestExpressionCaptureUnavailable(t *testing.T) {
+	var check = xycond.ExpectTrue
+	expectPanicMessage(t, "expect true, but got false", func() {
+		check(false).Assert("")
+	})
+}
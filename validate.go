@@ -0,0 +1,335 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/xybor-x/xyerror"
+)
+
+// tagName is the key of struct tags read by Validate.
+const tagName = "xycond"
+
+// FieldError is a failed validation rule of a field.
+type FieldError struct {
+	// Path is the path of the field from the validated value, e.g.
+	// Users[0].Name, it is len(Users[0].Name) for length rules.
+	Path string
+
+	// Rule is the failed rule, e.g. "min=1".
+	Rule string
+
+	// Condition is the false Condition of the rule.
+	Condition Condition
+}
+
+// Error implements error.
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Condition.Message()
+}
+
+// Is returns true if the target is xyerror.ValueError or its ancestors.
+func (e FieldError) Is(target error) bool {
+	return errors.Is(xyerror.ValueError, target)
+}
+
+// ValidationError contains all failed validation rules of a value.
+type ValidationError []FieldError
+
+// Error implements error, failed rules are separated by newlines.
+func (e ValidationError) Error() string {
+	var msgs = make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the FieldErrors.
+func (e ValidationError) Unwrap() []error {
+	var errs = make([]error, len(e))
+	for i := range e {
+		errs[i] = e[i]
+	}
+	return errs
+}
+
+// Validate checks fields of the struct by their xycond tags, e.g.
+//
+//	Name string `xycond:"required,min=1,max=100,match=^[a-z]+$"`
+//
+// Rules are separated by commas:
+//
+//   - required: the field is not zero, nil, or empty.
+//   - min=n, max=n: the number is in the bound, or the length of the string,
+//     slice, map, or array is in the bound. The length of a string is its
+//     number of runes.
+//   - in=a|b|c: the field is one of the values.
+//   - match=regexp: the string matches the regular expression. It must be the
+//     last rule because the expression may contain commas.
+//
+// Rules other than required are applied to the element of a pointer, and
+// skipped if the pointer is nil. Nested structs, including elements of
+// pointers, slices, arrays, and maps, are validated recursively. Validate
+// returns a ValidationError containing every failed rule, or nil. It panics if
+// a tag is invalid.
+func Validate(v any) error {
+	var w = validator{visited: map[visit]bool{}}
+	w.walk(reflect.ValueOf(v), "")
+	if len(w.errs) == 0 {
+		return nil
+	}
+	return w.errs
+}
+
+type validator struct {
+	errs    ValidationError
+	visited map[visit]bool
+}
+
+// walk validates structs reachable from the value at the path.
+func (w *validator) walk(v reflect.Value, path string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || w.visited[visitOf(v)] {
+			return
+		}
+		w.visited[visitOf(v)] = true
+		w.walk(v.Elem(), path)
+	case reflect.Interface:
+		if !v.IsNil() {
+			w.walk(v.Elem(), path)
+		}
+	case reflect.Struct:
+		var t = v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			var f = t.Field(i)
+			var tag = f.Tag.Get(tagName)
+			if !f.IsExported() || tag == "-" {
+				continue
+			}
+			var fieldPath = f.Name
+			if path != "" {
+				fieldPath = path + "." + f.Name
+			}
+			if tag != "" {
+				w.check(v.Field(i), fieldPath, tag)
+			}
+			w.walk(v.Field(i), fieldPath)
+		}
+	case reflect.Slice, reflect.Array:
+		if !mayContainStruct(v.Type().Elem()) {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			w.walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		if !mayContainStruct(v.Type().Elem()) {
+			return
+		}
+		var keys = v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return lessKey(keys[i], keys[j])
+		})
+		for _, key := range keys {
+			var keyPath = fmt.Sprintf("%s[%s]", path, Sprint(key.Interface()))
+			w.walk(v.MapIndex(key), keyPath)
+		}
+	}
+}
+
+// mayContainStruct returns true if values of the type may contain structs.
+func mayContainStruct(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Pointer, reflect.Interface, reflect.Slice,
+		reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// check applies rules of the tag to the field at the path.
+func (w *validator) check(v reflect.Value, path, tag string) {
+	for tag != "" {
+		var rule string
+		if strings.HasPrefix(tag, "match=") {
+			rule, tag = tag, ""
+		} else {
+			rule, tag, _ = strings.Cut(tag, ",")
+		}
+
+		var name, arg, _ = strings.Cut(rule, "=")
+		if name == "required" {
+			w.add(path, rule, required(v))
+			continue
+		}
+
+		var elem = v
+		for elem.Kind() == reflect.Pointer && !elem.IsNil() {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Pointer {
+			// Only required applies to a nil pointer, other rules are
+			// skipped.
+			continue
+		}
+
+		var c Condition
+		var rulePath = path
+		switch name {
+		case "min", "max":
+			c, rulePath = bound(elem, path, arg, name == "min")
+		case "in":
+			c = oneOf(elem, arg)
+		case "match":
+			c = match(elem, arg)
+		default:
+			Panicf("unknown rule %q of %s", rule, path)
+		}
+		w.add(rulePath, rule, c)
+	}
+}
+
+func (w *validator) add(path, rule string, c Condition) {
+	if !c.result {
+		w.errs = append(w.errs, FieldError{
+			Path:      path,
+			Rule:      rule,
+			Condition: c,
+		})
+	}
+}
+
+// required returns a true Condition if the value is not zero, nil, or empty.
+func required(v reflect.Value) Condition {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array,
+		reflect.Chan:
+		return ExpectNotEmpty(v.Interface())
+	case reflect.Pointer, reflect.Interface, reflect.Func:
+		return ExpectNotNil(v.Interface())
+	}
+	return NewCondition(!v.IsZero(), func() string {
+		return fmt.Sprintf("expect a non-zero value, but got %s",
+			Sprint(v.Interface()))
+	})
+}
+
+// bound returns a Condition of the min or max rule, and the path of the
+// checked value.
+func bound(v reflect.Value, path, arg string, min bool) (Condition, string) {
+	var invalid = func(err error) {
+		Panicf("invalid bound %q of %s: %s", arg, path, err)
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		var n, err = strconv.ParseInt(arg, 0, v.Type().Bits())
+		if err != nil {
+			invalid(err)
+		}
+		if v.Kind() == reflect.Int64 {
+			return boundOf(v.Int(), n, min), path
+		}
+		return boundOf(int(v.Int()), int(n), min), path
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		var n, err = strconv.ParseUint(arg, 0, 64)
+		if err != nil {
+			invalid(err)
+		}
+		return boundOf(v.Uint(), n, min), path
+	case reflect.Float32, reflect.Float64:
+		var n, err = strconv.ParseFloat(arg, 64)
+		if err != nil {
+			invalid(err)
+		}
+		return boundOf(v.Float(), n, min), path
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		var n, err = strconv.Atoi(arg)
+		if err != nil {
+			invalid(err)
+		}
+		var length = v.Len()
+		if v.Kind() == reflect.String {
+			length = utf8.RuneCountInString(v.String())
+		}
+		return boundOf(length, n, min), "len(" + path + ")"
+	}
+
+	Panicf("bound of %s of %s", v.Type(), path)
+	return Condition{}, ""
+}
+
+func boundOf[t number](v, n t, min bool) Condition {
+	if min {
+		return ExpectNotLessThan(v, n)
+	}
+	return ExpectNotGreaterThan(v, n)
+}
+
+// oneOf returns a Condition of the in rule.
+func oneOf(v reflect.Value, arg string) Condition {
+	var options = strings.Split(arg, "|")
+	var values = reflect.MakeSlice(reflect.SliceOf(v.Type()),
+		len(options), len(options))
+	for i := range options {
+		if err := parseText(values.Index(i), options[i]); err != nil {
+			Panicf("invalid option %q of %s: %s", options[i], v.Type(), err)
+		}
+	}
+	return ExpectIn(v.Interface(), values.Interface())
+}
+
+// regexps caches compiled expressions of match rules.
+var regexps sync.Map
+
+// match returns a Condition of the match rule.
+func match(v reflect.Value, expr string) Condition {
+	if v.Kind() != reflect.String {
+		Panicf("match of %s", v.Type())
+	}
+
+	var re, ok = regexps.Load(expr)
+	if !ok {
+		var compiled, err = regexp.Compile(expr)
+		if err != nil {
+			Panicf("invalid expression %q: %s", expr, err)
+		}
+		re, _ = regexps.LoadOrStore(expr, compiled)
+	}
+
+	return Expect(v.String(), NewMatcher("a string matching "+expr,
+		func(actual any) bool {
+			return re.(*regexp.Regexp).MatchString(actual.(string))
+		}))
+}
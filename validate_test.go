@@ -0,0 +1,156 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+	"github.com/xybor-x/xyerror"
+)
+
+type address struct {
+	City string `xycond:"required"`
+	Zip  string `xycond:"match=^[0-9]{5}$"`
+}
+
+type account struct {
+	Name     string             `xycond:"required,min=2,max=5"`
+	Age      int                `xycond:"min=0,max=150"`
+	Role     string             `xycond:"in=admin|guest"`
+	Level    *uint              `xycond:"in=1|2|3"`
+	Tags     []string           `xycond:"max=2"`
+	Home     *address           `xycond:"required"`
+	Others   []address          `xycond:""`
+	Contacts map[string]address `xycond:""`
+	Secret   string             `xycond:"-"`
+	private  string
+}
+
+func validAccount() account {
+	return account{
+		Name: "alice",
+		Role: "admin",
+		Home: &address{City: "Hanoi", Zip: "10000"},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var u = validAccount()
+	xycond.ExpectNil(xycond.Validate(u)).Test(t)
+	xycond.ExpectNil(xycond.Validate(&u)).Test(t)
+	xycond.ExpectNil(xycond.Validate([]account{u})).Test(t)
+	xycond.ExpectNil(xycond.Validate(nil)).Test(t)
+}
+
+func TestValidateFailures(t *testing.T) {
+	var level uint = 4
+	var u = validAccount()
+	u.Name = "ab©def"
+	u.Age = -1
+	u.Role = "root"
+	u.Level = &level
+	u.Tags = []string{"a", "b", "c"}
+	u.Home = nil
+	u.Others = []address{{City: "Hue", Zip: "1"}}
+	u.Contacts = map[string]address{"b": {Zip: "x"}, "a": {}}
+
+	var err = xycond.Validate(&u)
+	var verr xycond.ValidationError
+	xycond.ExpectTrue(errors.As(err, &verr)).Test(t)
+	xycond.ExpectTrue(errors.Is(err, xyerror.ValueError)).Test(t)
+
+	var paths []string
+	for _, e := range verr {
+		paths = append(paths, e.Path)
+	}
+	xycond.ExpectEqual(strings.Join(paths, "\n"), strings.Join([]string{
+		"len(Name)",
+		"Age",
+		"Role",
+		"Level",
+		"len(Tags)",
+		"Home",
+		"Others[0].Zip",
+		"Contacts[\"a\"].City",
+		"Contacts[\"a\"].Zip",
+		"Contacts[\"b\"].City",
+		"Contacts[\"b\"].Zip",
+	}, "\n")).Test(t)
+	xycond.ExpectEqual(verr[0].Rule, "max=5").Test(t)
+	xycond.ExpectEqual(verr[1].Error(), "Age: -1 is less than 0").Test(t)
+	xycond.ExpectEqual(strings.Count(err.Error(), "\n"), len(verr)-1).Test(t)
+}
+
+func TestValidateNilPointer(t *testing.T) {
+	type value struct {
+		P *int `xycond:"min=1,required"`
+		Q *int `xycond:"min=1"`
+	}
+
+	var err = xycond.Validate(value{})
+	xycond.ExpectError(err, xyerror.ValueError).Test(t)
+	var verr = err.(xycond.ValidationError)
+	xycond.ExpectEqual(len(verr), 1).Test(t)
+	xycond.ExpectEqual(verr[0].Path, "P").Test(t)
+	xycond.ExpectEqual(verr[0].Rule, "required").Test(t)
+
+	var one = 1
+	xycond.ExpectNil(xycond.Validate(value{P: &one})).Test(t)
+}
+
+type cyclic struct {
+	Name string `xycond:"required"`
+	Next *cyclic
+}
+
+func TestValidateCycle(t *testing.T) {
+	var c = &cyclic{}
+	c.Next = c
+	var err = xycond.Validate(c)
+	xycond.ExpectError(err, xyerror.ValueError).Test(t)
+	xycond.ExpectEqual(len(err.(xycond.ValidationError)), 1).Test(t)
+}
+
+func TestValidateInvalidTag(t *testing.T) {
+	xycond.ExpectPanic(xyerror.AssertionError, func() {
+		xycond.Validate(struct {
+			A int `xycond:"unknown"`
+		}{})
+	}).Test(t)
+	xycond.ExpectPanic(xyerror.AssertionError, func() {
+		xycond.Validate(struct {
+			A int `xycond:"min=x"`
+		}{})
+	}).Test(t)
+	xycond.ExpectPanic(xyerror.AssertionError, func() {
+		xycond.Validate(struct {
+			A int `xycond:"in=1|x"`
+		}{})
+	}).Test(t)
+	xycond.ExpectPanic(xyerror.AssertionError, func() {
+		xycond.Validate(struct {
+			A string `xycond:"match=("`
+		}{})
+	}).Test(t)
+}
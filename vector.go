@@ -0,0 +1,286 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Vector is a record decoded from a test-vector file.
+type Vector[T any] struct {
+	// File is the path of the test-vector file.
+	File string
+
+	// Record is the 1-based index of the record in the file.
+	Record int
+
+	// Value is the decoded record.
+	Value T
+}
+
+// LoadVectors decodes records of a test-vector file by its extension:
+//
+//   - .json: an array of values.
+//   - .csv: a header row with field names followed by records.
+//   - .txtar: an archive whose files are named "<record>/<field>", or
+//     "<field>" if the archive contains only one record.
+//
+// Fields of CSV and txtar records are matched with struct fields by their json
+// tag names or their names, case-insensitively. Field values are parsed as
+// strings, byte slices, numbers, booleans, encoding.TextUnmarshaler, or JSON.
+func LoadVectors[T any](path string) ([]Vector[T], error) {
+	var data, err = os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []T
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &values)
+	case ".csv":
+		values, err = decodeCSV[T](data)
+	case ".txtar":
+		values, err = decodeTxtar[T](data)
+	default:
+		err = fmt.Errorf("unsupported test-vector file %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var vectors = make([]Vector[T], len(values))
+	for i := range values {
+		vectors[i] = Vector[T]{File: path, Record: i + 1, Value: values[i]}
+	}
+	return vectors, nil
+}
+
+// vectorRunner instances may be *testing.T, whose subtests are of the same
+// type.
+type vectorRunner[T any] interface {
+	failer
+	Helper()
+	Fatal(args ...any)
+	Logf(format string, args ...any)
+	Run(name string, f func(t T)) bool
+}
+
+// RunVectors runs each record of the test-vector file as a subtest which
+// tests the Condition returned by check. The file and record number are logged
+// when the Condition is false. The test fails immediately if the file can't be
+// loaded. The test is usually a *testing.T.
+func RunVectors[T any, R vectorRunner[R]](
+	t R, path string, check func(v T) Condition,
+) {
+	t.Helper()
+	var _, file, line, _ = runtime.Caller(1)
+
+	var vectors, err = LoadVectors[T](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range vectors {
+		var v = v
+		t.Run(fmt.Sprintf("%s#%d", filepath.Base(path), v.Record),
+			func(t R) {
+				if c := check(v.Value); !c.OK() {
+					t.Logf("%s: record %d", v.File, v.Record)
+					c.test(t, file, line)
+				}
+			})
+	}
+}
+
+func decodeCSV[T any](data []byte) ([]T, error) {
+	var rows, err = csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	var values = make([]T, len(rows)-1)
+	for i, row := range rows[1:] {
+		var v = reflect.ValueOf(&values[i]).Elem()
+		for j := range row {
+			if err := setVectorField(v, rows[0][j], row[j]); err != nil {
+				return nil, fmt.Errorf("record %d: %w", i+1, err)
+			}
+		}
+	}
+	return values, nil
+}
+
+func decodeTxtar[T any](data []byte) ([]T, error) {
+	var files = parseTxtar(data)
+
+	// Group files into records in the order of their first appearance.
+	var names []string
+	var records = map[string][]txtarFile{}
+	for _, f := range files {
+		var record, field, ok = strings.Cut(f.name, "/")
+		if !ok {
+			record, field = "", f.name
+		}
+		if _, ok := records[record]; !ok {
+			names = append(names, record)
+		}
+		records[record] = append(records[record],
+			txtarFile{name: field, data: f.data})
+	}
+
+	var values = make([]T, len(names))
+	for i, name := range names {
+		var v = reflect.ValueOf(&values[i]).Elem()
+		for _, f := range records[name] {
+			if err := setVectorField(v, f.name, string(f.data)); err != nil {
+				return nil, fmt.Errorf("record %d: %w", i+1, err)
+			}
+		}
+	}
+	return values, nil
+}
+
+type txtarFile struct {
+	name string
+	data []byte
+}
+
+// parseTxtar parses a txtar archive, which is a comment followed by files
+// started with "-- name --" lines. The comment is ignored.
+func parseTxtar(data []byte) []txtarFile {
+	var files []txtarFile
+	var current *txtarFile
+	for len(data) > 0 {
+		var line []byte
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line, data = data[:i+1], data[i+1:]
+		} else {
+			line, data = data, nil
+		}
+
+		var trimmed = bytes.TrimRight(line, "\r\n")
+		if bytes.HasPrefix(trimmed, []byte("-- ")) &&
+			bytes.HasSuffix(trimmed, []byte(" --")) && len(trimmed) > 6 {
+			files = append(files, txtarFile{
+				name: string(bytes.TrimSpace(trimmed[3 : len(trimmed)-3])),
+			})
+			current = &files[len(files)-1]
+			continue
+		}
+		if current != nil {
+			current.data = append(current.data, line...)
+		}
+	}
+	return files
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).
+	Elem()
+
+// setVectorField parses the text into the struct field matching the name.
+func setVectorField(v reflect.Value, name, text string) error {
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("%s is not a struct", v.Type())
+	}
+
+	var field = vectorField(v, name)
+	if !field.IsValid() {
+		return fmt.Errorf("no field %q in %s", name, v.Type())
+	}
+
+	if err := parseText(field, text); err != nil {
+		return fmt.Errorf("field %q: %w", name, err)
+	}
+	return nil
+}
+
+// parseText parses the text into the addressable value as a string, byte
+// slice, number, boolean, encoding.TextUnmarshaler, or JSON.
+func parseText(v reflect.Value, text string) error {
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).
+			UnmarshalText([]byte(text))
+	}
+
+	var err error
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(strings.TrimSpace(text))
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(strings.TrimSpace(text), 0, v.Type().Bits())
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		n, err = strconv.ParseUint(strings.TrimSpace(text), 0,
+			v.Type().Bits())
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(strings.TrimSpace(text), v.Type().Bits())
+		v.SetFloat(f)
+	default:
+		if v.Kind() == reflect.Slice &&
+			v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(text))
+			return nil
+		}
+		err = json.Unmarshal([]byte(text), v.Addr().Interface())
+	}
+	return err
+}
+
+// vectorField returns the exported field whose json tag name or name matches
+// the name case-insensitively.
+func vectorField(v reflect.Value, name string) reflect.Value {
+	var t = v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		var f = t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		var tag, _, _ = strings.Cut(f.Tag.Get("json"), ",")
+		if strings.EqualFold(tag, name) || strings.EqualFold(f.Name, name) {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
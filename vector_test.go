@@ -0,0 +1,134 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xycond_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+)
+
+type addVector struct {
+	A   int `json:"a"`
+	B   int `json:"b"`
+	Sum int `json:"sum"`
+}
+
+type upperVector struct {
+	Input  string
+	Output string
+}
+
+func TestLoadVectors(t *testing.T) {
+	var vectors, err = xycond.LoadVectors[addVector]("testdata/vectors/add.csv")
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(len(vectors), 3).Test(t)
+	xycond.ExpectEqual(vectors[2].Record, 3).Test(t)
+	xycond.ExpectEqual(vectors[2].File, "testdata/vectors/add.csv").Test(t)
+	xycond.ExpectEqual(vectors[2].Value, addVector{16, 1, 17}).Test(t)
+
+	upper, err := xycond.LoadVectors[upperVector](
+		"testdata/vectors/upper.txtar")
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(len(upper), 2).Test(t)
+	xycond.ExpectEqual(upper[1].Value,
+		upperVector{"foo\nbar\n", "FOO\nBAR\n"}).Test(t)
+
+	upper, err = xycond.LoadVectors[upperVector](
+		"testdata/vectors/single.txtar")
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(len(upper), 1).Test(t)
+	xycond.ExpectEqual(upper[0].Value,
+		upperVector{"abc\n", "ABC\n"}).Test(t)
+}
+
+func TestLoadVectorsError(t *testing.T) {
+	var _, err = xycond.LoadVectors[addVector]("testdata/vectors/bad.csv")
+	xycond.ExpectNotNil(err).Test(t)
+	xycond.ExpectIn(`record 1: field "b"`, err.Error()).Test(t)
+
+	_, err = xycond.LoadVectors[addVector]("testdata/vectors/add.xml")
+	xycond.ExpectNotNil(err).Test(t)
+
+	_, err = xycond.LoadVectors[int]("testdata/vectors/add.csv")
+	xycond.ExpectNotNil(err).Test(t)
+}
+
+func TestRunVectors(t *testing.T) {
+	var check = func(v addVector) xycond.Condition {
+		return xycond.ExpectEqual(v.A+v.B, v.Sum)
+	}
+	xycond.RunVectors(t, "testdata/vectors/add.json", check)
+	xycond.RunVectors(t, "testdata/vectors/add.csv", check)
+
+	xycond.RunVectors(t, "testdata/vectors/upper.txtar",
+		func(v upperVector) xycond.Condition {
+			return xycond.ExpectEqual(strings.ToUpper(v.Input), v.Output)
+		})
+}
+
+// fakeVectorRunner runs subtests of RunVectors in sequence and records their
+// failures and logs.
+type fakeVectorRunner struct {
+	failed bool
+	fatal  bool
+	logs   []string
+}
+
+func (r *fakeVectorRunner) Fail()             { r.failed = true }
+func (r *fakeVectorRunner) Helper()           {}
+func (r *fakeVectorRunner) Fatal(args ...any) { r.failed, r.fatal = true, true }
+
+func (r *fakeVectorRunner) Logf(format string, args ...any) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func (r *fakeVectorRunner) Run(
+	name string, f func(t *fakeVectorRunner),
+) bool {
+	var sub = &fakeVectorRunner{}
+	f(sub)
+	r.failed = r.failed || sub.failed
+	r.logs = append(r.logs, sub.logs...)
+	return !sub.failed
+}
+
+func TestRunVectorsFailure(t *testing.T) {
+	var r = &fakeVectorRunner{}
+	captureStdout(t, func() {
+		xycond.RunVectors(r, "testdata/vectors/add.csv",
+			func(v addVector) xycond.Condition {
+				return xycond.ExpectGreaterThan(v.A, 0)
+			})
+	})
+	xycond.ExpectTrue(r.failed).Test(t)
+	xycond.ExpectFalse(r.fatal).Test(t)
+	xycond.ExpectEqual(len(r.logs), 1).Test(t)
+	xycond.ExpectEqual(r.logs[0], "testdata/vectors/add.csv: record 2").Test(t)
+
+	r = &fakeVectorRunner{}
+	xycond.RunVectors(r, "testdata/vectors/missing.csv",
+		func(v addVector) xycond.Condition { return xycond.ExpectTrue(true) })
+	xycond.ExpectTrue(r.fatal).Test(t)
+	xycond.ExpectEqual(len(r.logs), 0).Test(t)
+}
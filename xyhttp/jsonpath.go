@@ -0,0 +1,90 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xyhttp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// evalJSONPath returns the value at the path of the decoded JSON value.
+func evalJSONPath(v any, path string) (any, error) {
+	var rest, ok = strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, fmt.Errorf("the path must start with $")
+	}
+
+	for rest != "" {
+		var key string
+		var index = -1
+		switch {
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			var end = strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key, rest = rest[:end], rest[end:]
+		case strings.HasPrefix(rest, "['"):
+			var end = strings.Index(rest, "']")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed bracket in %s", rest)
+			}
+			key, rest = rest[2:end], rest[end+2:]
+		case strings.HasPrefix(rest, "["):
+			var end = strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed bracket in %s", rest)
+			}
+			var err error
+			index, err = strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index %s", rest[:end+1])
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %s", rest)
+		}
+
+		if index >= 0 {
+			var array, ok = v.([]any)
+			if !ok {
+				return nil, fmt.Errorf("index %d of a non-array", index)
+			}
+			if index >= len(array) {
+				return nil, fmt.Errorf("index %d out of %d elements",
+					index, len(array))
+			}
+			v = array[index]
+			continue
+		}
+
+		var object, isObject = v.(map[string]any)
+		if !isObject {
+			return nil, fmt.Errorf("field %q of a non-object", key)
+		}
+		if v, ok = object[key]; !ok {
+			return nil, fmt.Errorf("no field %q", key)
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xyhttp_test
+
+import (
+	"testing"
+
+	"github.com/xybor-x/xycond"
+	"github.com/xybor-x/xycond/xyhttp"
+)
+
+func TestExpectJSONPath(t *testing.T) {
+	var messages = map[string]string{
+		"$.name":     `expect $.name to be "bob", but got "alice"`,
+		"$.email":    `$.email: no field "email"`,
+		"$.roles[1]": "$.roles[1]: index 1 out of 1 elements",
+		"$.name[0]":  "$.name[0]: index 0 of a non-array",
+		"$.age.x":    `$.age.x: field "x" of a non-object`,
+		"$['name'":   "$['name': unclosed bracket in ['name'",
+		"name":       "name: the path must start with $",
+	}
+	for path, msg := range messages {
+		var c = xyhttp.Expect(mux).GET("/users/1").
+			WithHeader("Authorization", "token").
+			JSONPath(path, "bob").
+			Condition()
+		xycond.ExpectIn(msg, c.Message()).Test(t)
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package xyhttp expects responses of an http.Handler, which is served by
+// httptest, as xycond Conditions.
+package xyhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/xybor-x/xycond"
+)
+
+// MaxBodyLength is the maximum number of bytes of a body printed in failure
+// messages.
+var MaxBodyLength = 1024
+
+// Client sends requests to an http.Handler.
+type Client struct {
+	handler http.Handler
+}
+
+// Expect returns a Client sending requests to the handler.
+func Expect(handler http.Handler) *Client {
+	return &Client{handler: handler}
+}
+
+// GET returns a GET Request to the target.
+func (c *Client) GET(target string) *Request {
+	return c.Do(http.MethodGet, target)
+}
+
+// HEAD returns a HEAD Request to the target.
+func (c *Client) HEAD(target string) *Request {
+	return c.Do(http.MethodHead, target)
+}
+
+// POST returns a POST Request to the target.
+func (c *Client) POST(target string) *Request {
+	return c.Do(http.MethodPost, target)
+}
+
+// PUT returns a PUT Request to the target.
+func (c *Client) PUT(target string) *Request {
+	return c.Do(http.MethodPut, target)
+}
+
+// PATCH returns a PATCH Request to the target.
+func (c *Client) PATCH(target string) *Request {
+	return c.Do(http.MethodPatch, target)
+}
+
+// DELETE returns a DELETE Request to the target.
+func (c *Client) DELETE(target string) *Request {
+	return c.Do(http.MethodDelete, target)
+}
+
+// Do returns a Request with the method to the target.
+func (c *Client) Do(method, target string) *Request {
+	return &Request{
+		client: c,
+		method: method,
+		target: target,
+		header: http.Header{},
+	}
+}
+
+// Request is a request to an http.Handler with expectations of its response.
+// The request is served once, when the first Condition is evaluated, and
+// expectations must not be added after that.
+type Request struct {
+	client *Client
+	method string
+	target string
+	header http.Header
+	body   []byte
+
+	checks   []check
+	response *httptest.ResponseRecorder
+	respBody []byte
+	err      error
+}
+
+// check returns an empty string if the response is expected, or the reason.
+type check func(r *httptest.ResponseRecorder) string
+
+// WithHeader adds a header to the request.
+func (r *Request) WithHeader(key, value string) *Request {
+	r.header.Add(key, value)
+	return r
+}
+
+// WithBody sets the body of the request.
+func (r *Request) WithBody(body string) *Request {
+	r.body = []byte(body)
+	return r
+}
+
+// WithJSON sets the JSON encoded value as the body of the request, and sets
+// its Content-Type to application/json.
+func (r *Request) WithJSON(v any) *Request {
+	var body, err = json.Marshal(v)
+	if err != nil {
+		r.err = fmt.Errorf("encode the body: %w", err)
+	}
+	r.body = body
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// Status expects the status code of the response.
+func (r *Request) Status(code int) *Request {
+	return r.expect(func(w *httptest.ResponseRecorder) string {
+		if w.Code != code {
+			return fmt.Sprintf("expect status %d, but got %d", code, w.Code)
+		}
+		return ""
+	})
+}
+
+// Header expects the value of the header of the response.
+func (r *Request) Header(key, value string) *Request {
+	return r.expect(func(w *httptest.ResponseRecorder) string {
+		var values = w.Result().Header.Values(key)
+		for i := range values {
+			if values[i] == value {
+				return ""
+			}
+		}
+		if len(values) == 0 {
+			return fmt.Sprintf("expect header %s: %s, but got no value",
+				key, value)
+		}
+		return fmt.Sprintf("expect header %s: %s, but got %s",
+			key, value, strings.Join(values, ", "))
+	})
+}
+
+// JSONPath expects the value at the path of the JSON body of the response.
+// The path supports the root "$", fields ".name" or "['name']", and indexes
+// "[0]". The wanted value is compared with the value at the path after
+// encoding it to JSON and decoding it back.
+func (r *Request) JSONPath(path string, want any) *Request {
+	return r.expect(func(w *httptest.ResponseRecorder) string {
+		var got any
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			return fmt.Sprintf("expect a JSON body, but got %s", err)
+		}
+
+		got, err := evalJSONPath(got, path)
+		if err != nil {
+			return fmt.Sprintf("%s: %s", path, err)
+		}
+
+		var normalized any
+		if data, err := json.Marshal(want); err != nil {
+			return fmt.Sprintf("%s: encode %s: %s", path,
+				xycond.Sprint(want), err)
+		} else if err := json.Unmarshal(data, &normalized); err != nil {
+			return fmt.Sprintf("%s: decode %s: %s", path,
+				xycond.Sprint(want), err)
+		}
+
+		if !reflect.DeepEqual(got, normalized) {
+			return fmt.Sprintf("expect %s to be %s, but got %s",
+				path, xycond.Sprint(normalized), xycond.Sprint(got))
+		}
+		return ""
+	})
+}
+
+// Body expects the body of the response.
+func (r *Request) Body(want string) *Request {
+	return r.expect(func(w *httptest.ResponseRecorder) string {
+		if w.Body.String() != want {
+			return fmt.Sprintf("expect body %s, but got %s",
+				xycond.Sprint(truncate(want)),
+				xycond.Sprint(truncate(w.Body.String())))
+		}
+		return ""
+	})
+}
+
+// Condition serves the request and returns a true Condition if the response
+// meets all expectations. The failure message contains failed expectations,
+// the request, and the response, their bodies are truncated to MaxBodyLength.
+func (r *Request) Condition() xycond.Condition {
+	var w = r.serve()
+	var failures []string
+	if r.err != nil {
+		failures = append(failures, r.err.Error())
+	} else {
+		for _, check := range r.checks {
+			if msg := check(w); msg != "" {
+				failures = append(failures, msg)
+			}
+		}
+	}
+
+	return xycond.NewCondition(len(failures) == 0, func() string {
+		return r.describe(w, failures)
+	})
+}
+
+// Test tests the Condition of the request, see xycond.Condition.Test.
+func (r *Request) Test(t interface{ Fail() }) {
+	r.Condition().Test(t)
+}
+
+// Assert asserts the Condition of the request, see xycond.Condition.Assert.
+func (r *Request) Assert(msg string) {
+	r.Condition().Assert(msg)
+}
+
+func (r *Request) expect(c check) *Request {
+	r.checks = append(r.checks, c)
+	return r
+}
+
+// serve serves the request if it has not been served and returns the
+// response.
+func (r *Request) serve() *httptest.ResponseRecorder {
+	if r.response == nil {
+		var req = httptest.NewRequest(r.method, r.target,
+			bytes.NewReader(r.body))
+		for key, values := range r.header {
+			req.Header[key] = values
+		}
+		r.response = httptest.NewRecorder()
+		r.client.handler.ServeHTTP(r.response, req)
+
+		// The body of w.Result() can only be read once, so the body is
+		// captured for failure messages which may be rendered many times.
+		r.respBody = r.response.Body.Bytes()
+	}
+	return r.response
+}
+
+func (r *Request) describe(
+	w *httptest.ResponseRecorder,
+	failures []string,
+) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s:", r.method, r.target)
+	for _, f := range failures {
+		fmt.Fprintf(&sb, "\n    %s", f)
+	}
+
+	sb.WriteString("\nrequest:")
+	writeMessage(&sb, r.method+" "+r.target, r.header, r.body)
+
+	var resp = w.Result()
+	sb.WriteString("\nresponse:")
+	writeMessage(&sb, resp.Proto+" "+resp.Status, resp.Header, r.respBody)
+	return sb.String()
+}
+
+// writeMessage writes the start line, the sorted headers, and the truncated
+// body of an HTTP message, which are indented.
+func writeMessage(
+	sb *strings.Builder, start string, header http.Header, body []byte,
+) {
+	fmt.Fprintf(sb, "\n    %s", start)
+
+	var keys = make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(sb, "\n    %s: %s", key, value)
+		}
+	}
+
+	if len(body) > 0 {
+		sb.WriteString("\n")
+		var lines = strings.TrimSuffix(truncate(string(body)), "\n")
+		for _, line := range strings.Split(lines, "\n") {
+			sb.WriteString("\n")
+			if line != "" {
+				sb.WriteString("    " + line)
+			}
+		}
+	}
+}
+
+// truncate truncates the body to MaxBodyLength bytes.
+func truncate(body string) string {
+	if MaxBodyLength > 0 && len(body) > MaxBodyLength {
+		return fmt.Sprintf("%s...(%d more bytes)",
+			body[:MaxBodyLength], len(body)-MaxBodyLength)
+	}
+	return body
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2022 xybor-x
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xyhttp_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xybor-x/xycond"
+	"github.com/xybor-x/xycond/xyhttp"
+)
+
+type mocktest struct {
+	failed bool
+}
+
+func (m *mocktest) Fail() {
+	m.failed = true
+}
+
+var mux = http.NewServeMux()
+
+func init() {
+	mux.HandleFunc("/users/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"name":"alice","age":30,"roles":["admin"]}`)
+	})
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		var v any
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		json.NewEncoder(w).Encode(v)
+	})
+}
+
+func TestExpect(t *testing.T) {
+	xyhttp.Expect(mux).GET("/users/1").
+		WithHeader("Authorization", "token").
+		Status(http.StatusOK).
+		Header("Content-Type", "application/json").
+		JSONPath("$.name", "alice").
+		JSONPath("$.age", 30).
+		JSONPath("$.roles[0]", "admin").
+		JSONPath("$['roles']", []string{"admin"}).
+		Test(t)
+
+	xyhttp.Expect(mux).POST("/echo").
+		WithJSON(map[string]int{"id": 1}).
+		Status(http.StatusOK).
+		Header("Content-Type", "application/json").
+		Body("{\"id\":1}\n").
+		Assert("")
+
+	xyhttp.Expect(mux).GET("/users/1").
+		Status(http.StatusUnauthorized).
+		Condition().Test(t)
+}
+
+func TestExpectFailure(t *testing.T) {
+	var c = xyhttp.Expect(mux).GET("/users/1").
+		Status(http.StatusOK).
+		Header("Content-Type", "application/json").
+		JSONPath("$.name", "alice").
+		Condition()
+
+	xycond.ExpectFalse(c.OK()).Test(t)
+	xycond.ExpectEqual(c.Message(), strings.Join([]string{
+		"GET /users/1:",
+		"    expect status 200, but got 401",
+		"    expect header Content-Type: application/json, " +
+			"but got text/plain; charset=utf-8",
+		"    expect a JSON body, " +
+			"but got invalid character 'u' looking for beginning of value",
+		"request:",
+		"    GET /users/1",
+		"response:",
+		"    HTTP/1.1 401 Unauthorized",
+		"    Content-Type: text/plain; charset=utf-8",
+		"    X-Content-Type-Options: nosniff",
+		"",
+		"    unauthorized",
+	}, "\n")).Test(t)
+}
+
+func TestExpectFailureRequest(t *testing.T) {
+	var c = xyhttp.Expect(mux).POST("/echo").
+		WithHeader("Authorization", "token").
+		WithBody(`{"id":`).
+		Status(http.StatusOK).
+		Condition()
+
+	// The message can be rendered many times.
+	var msg = c.Message()
+	xycond.ExpectEqual(c.Message(), msg).Test(t)
+	xycond.ExpectEqual(msg, strings.Join([]string{
+		"POST /echo:",
+		"    expect status 200, but got 400",
+		"request:",
+		"    POST /echo",
+		"    Authorization: token",
+		"",
+		`    {"id":`,
+		"response:",
+		"    HTTP/1.1 400 Bad Request",
+		"    Content-Type: text/plain; charset=utf-8",
+		"    X-Content-Type-Options: nosniff",
+		"",
+		"    unexpected EOF",
+	}, "\n")).Test(t)
+}
+
+func TestExpectTruncatedBody(t *testing.T) {
+	var handler = http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, strings.Repeat("x", xyhttp.MaxBodyLength+10))
+		})
+	var c = xyhttp.Expect(handler).POST("/").
+		WithBody(strings.Repeat("y", xyhttp.MaxBodyLength+5)).
+		Body("").
+		Condition()
+	xycond.ExpectIn("...(5 more bytes)", c.Message()).Test(t)
+	xycond.ExpectIn("...(10 more bytes)", c.Message()).Test(t)
+}
+
+func TestExpectLocation(t *testing.T) {
+	var file string
+	var remove = xycond.OnFailure(func(e xycond.FailureEvent) {
+		file = e.File
+	})
+	defer remove()
+
+	var m = &mocktest{}
+	xyhttp.Expect(mux).GET("/users/1").Status(http.StatusOK).Test(m)
+	xycond.ExpectTrue(m.failed).Test(t)
+	xycond.ExpectEqual(filepath.Base(file), "xyhttp_test.go").Test(t)
+}